@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/thetronjohnson/layrr/pkg/agent"
 	"github.com/thetronjohnson/layrr/pkg/assetserver"
 	"github.com/thetronjohnson/layrr/pkg/bridge"
 	"github.com/thetronjohnson/layrr/pkg/claude"
@@ -27,9 +28,11 @@ type App struct {
 	watcher           *watcher.Watcher
 	bridge            *bridge.Bridge
 	claudeManager     *claude.Manager
+	backend           agent.Backend
 	statusDisplay     *status.Display
 	gitManager        *git.GitManager
 	devServerManager  *devserver.Manager
+	devConfigWatcher  *devserver.Watcher
 	projectDir        string
 	assetPort         int
 	targetPort        int
@@ -101,9 +104,9 @@ func (a *App) StartProxy(projectPath string, targetPort int) string {
 		a.targetPort = detectedPort
 	}
 
-	// Ensure Anthropic API key is available
+	// Ensure credentials for the selected backend are available
 	if err = a.ensureAPIKey(); err != nil {
-		return fmt.Sprintf("Error: %v. Please set your Anthropic API key.", err)
+		return fmt.Sprintf("Error: %v.", err)
 	}
 
 	// Start Claude Code manager
@@ -114,17 +117,66 @@ func (a *App) StartProxy(projectPath string, targetPort int) string {
 	}
 	fmt.Printf("[App] ✅ Claude Manager created successfully\n")
 
-	// Create bridge
-	a.bridge = bridge.NewBridge(a.claudeManager, false, a.statusDisplay)
+	// Build the selected AI backend (defaults to the Claude Code CLI above)
+	backendCfg, err := config.GetBackendConfig()
+	if err != nil {
+		backendCfg = config.BackendConfig{Backend: agent.BackendClaude}
+	}
+	a.backend, err = agent.New(backendCfg.Backend, a.claudeManager, backendCfg)
+	if err != nil {
+		return fmt.Sprintf("Error initializing AI backend %q: %v", backendCfg.Backend, err)
+	}
+	// Wrap it so every turn is automatically checkpointed, regardless of
+	// which backend is selected
+	a.backend = agent.NewCheckpointingBackend(a.backend, a.gitManager)
 
-	// Start file watcher
-	a.watcher, err = watcher.NewWatcher(a.projectDir, false, a.statusDisplay)
+	// Create bridge, dispatching through the selected backend rather than
+	// the Claude Code manager directly so switching backends via SetBackend
+	// actually changes where a turn is sent
+	a.bridge = bridge.NewBridge(a.backend, false, a.statusDisplay)
+
+	// Start file watcher, honoring any per-project watch options the user has saved
+	savedWatchOpts, err := config.GetWatchOptions(a.projectDir)
+	if err != nil {
+		savedWatchOpts = config.WatchOptionsData{}
+	}
+	a.watcher, err = watcher.NewWatcher(a.projectDir, watcher.WatchOptions{
+		ExtraIgnores:    savedWatchOpts.ExtraIgnores,
+		ExtraExtensions: savedWatchOpts.ExtraExtensions,
+		DebounceMs:      savedWatchOpts.DebounceMs,
+	}, false, a.statusDisplay)
 	if err != nil {
 		return fmt.Sprintf("Error starting file watcher: %v", err)
 	}
 
+	// Mint a fresh session token authenticating this run's /__layrr/* endpoints
+	sessionToken, err := config.NewSessionToken(a.projectDir)
+	if err != nil {
+		return fmt.Sprintf("Error creating session token: %v", err)
+	}
+
 	// Create asset server (which also proxies to dev server)
-	a.assetServer = assetserver.NewServer(a.assetPort, a.targetPort, a.projectDir, a.bridge, a.watcher, false)
+	a.assetServer = assetserver.NewServer(a.assetPort, a.targetPort, a.projectDir, a.bridge, a.watcher, false, nil, sessionToken)
+	if a.devServerManager != nil {
+		a.assetServer.SetDevServerManager(a.devServerManager)
+	}
+	if savedProfile, err := config.GetNetworkProfile(); err == nil {
+		a.assetServer.SetNetworkProfile(proxy.NetworkProfile{
+			Name:      savedProfile.Name,
+			ReadKBps:  savedProfile.ReadKBps,
+			WriteKBps: savedProfile.WriteKBps,
+			LatencyMs: savedProfile.LatencyMs,
+			LossPct:   savedProfile.LossPct,
+		})
+	}
+	if savedState, err := config.LoadProjectState(a.projectDir); err == nil {
+		a.assetServer.SetProxySettings(proxy.Settings{
+			ExtraHeaders:    savedState.Settings.ExtraHeaders,
+			HeaderOverrides: savedState.Settings.HeaderOverrides,
+			CookieOverrides: savedState.Settings.CookieOverrides,
+			AllowedHosts:    savedState.Settings.AllowedHosts,
+		})
+	}
 
 	// Start asset server in goroutine
 	go func() {
@@ -161,6 +213,11 @@ func (a *App) StopProxy() string {
 		a.watcher.Close()
 	}
 
+	// Stop the config-change watcher driving dev server restarts
+	if a.devConfigWatcher != nil {
+		a.devConfigWatcher.Close()
+	}
+
 	// Stop dev server if running
 	if a.devServerManager != nil {
 		log.Println("Stopping dev server...")
@@ -241,6 +298,40 @@ func (a *App) RemoveRecentProject(path string) error {
 	return config.RemoveRecentProject(path)
 }
 
+// GetMaxRecentProjects returns the user's configured recent-projects limit
+func (a *App) GetMaxRecentProjects() (int, error) {
+	return config.GetMaxRecentProjects()
+}
+
+// SetMaxRecentProjects changes how many projects the recent-projects list keeps
+func (a *App) SetMaxRecentProjects(maxRecent int) error {
+	return config.SetMaxRecentProjects(maxRecent)
+}
+
+// GetProjectState returns the persisted sidebar layout and proxy settings for path
+func (a *App) GetProjectState(path string) (config.ProjectState, error) {
+	return config.LoadProjectState(path)
+}
+
+// SaveProjectState persists the sidebar layout and proxy settings for path,
+// applying the proxy settings to the live asset server if it's serving that project
+func (a *App) SaveProjectState(path string, state config.ProjectState) error {
+	if err := config.SaveProjectState(path, state); err != nil {
+		return err
+	}
+
+	if a.assetServer != nil && a.projectDir == path {
+		a.assetServer.SetProxySettings(proxy.Settings{
+			ExtraHeaders:    state.Settings.ExtraHeaders,
+			HeaderOverrides: state.Settings.HeaderOverrides,
+			CookieOverrides: state.Settings.CookieOverrides,
+			AllowedHosts:    state.Settings.AllowedHosts,
+		})
+	}
+
+	return nil
+}
+
 // OpenRecentProject loads a recent project
 func (a *App) OpenRecentProject(path string, targetPort int) string {
 	// Verify directory still exists
@@ -277,8 +368,32 @@ func (a *App) DetectPortsWithInfo() []proxy.PortInfo {
 	return proxy.DetectPortsWithInfo()
 }
 
-// ensureAPIKey checks for Anthropic API key
+// ensureAPIKey checks that credentials for the currently selected backend are
+// available, consulting pkg/config for the user's backend choice
 func (a *App) ensureAPIKey() error {
+	backendCfg, err := config.GetBackendConfig()
+	if err != nil {
+		backendCfg = config.BackendConfig{Backend: agent.BackendClaude}
+	}
+
+	if backendCfg.Backend == "" || backendCfg.Backend == agent.BackendClaude {
+		return a.ensureAnthropicAPIKey()
+	}
+
+	// Ollama runs fully locally, so it needs no credentials
+	if backendCfg.Backend == agent.BackendOllama {
+		return nil
+	}
+
+	if backendCfg.APIKeys[backendCfg.Backend] == "" {
+		return fmt.Errorf("%s API key not found. Please set it in the app", backendCfg.Backend)
+	}
+
+	return nil
+}
+
+// ensureAnthropicAPIKey checks for the Anthropic API key the Claude Code CLI needs
+func (a *App) ensureAnthropicAPIKey() error {
 	// Try to find existing API key
 	_, err := config.GetAnthropicAPIKey(a.projectDir)
 	if err == nil {
@@ -306,6 +421,53 @@ func (a *App) ensureAPIKey() error {
 	return fmt.Errorf("Anthropic API key not found. Please set it in the app")
 }
 
+// ListBackends returns the built-in AI backend identifiers for the frontend's model picker
+func (a *App) ListBackends() []string {
+	return agent.Names()
+}
+
+// SetBackend selects which AI backend subsequent turns should use and persists
+// the choice. If the proxy is already running, the active backend is rebuilt
+// immediately; otherwise it takes effect on the next StartProxy call.
+func (a *App) SetBackend(name string) error {
+	backendCfg, err := config.GetBackendConfig()
+	if err != nil {
+		backendCfg = config.BackendConfig{}
+	}
+	backendCfg.Backend = name
+
+	if err := config.SetBackendConfig(backendCfg); err != nil {
+		return fmt.Errorf("failed to save backend selection: %w", err)
+	}
+
+	if a.claudeManager != nil {
+		backend, err := agent.New(name, a.claudeManager, backendCfg)
+		if err != nil {
+			return err
+		}
+		a.backend = agent.NewCheckpointingBackend(backend, a.gitManager)
+
+		// Rebuild the bridge around the new backend and reinject it into the
+		// asset server, which holds its own reference and otherwise keeps
+		// dispatching to whichever backend was active when it was built
+		a.bridge = bridge.NewBridge(a.backend, false, a.statusDisplay)
+		if a.assetServer != nil {
+			a.assetServer.SetBridge(a.bridge)
+		}
+	}
+
+	return nil
+}
+
+// SetBackendCredentials saves the API key, base URL, and/or model override for
+// one backend, leaving the currently selected backend unchanged
+func (a *App) SetBackendCredentials(name, apiKey, baseURL, model string) error {
+	if name == "" {
+		return fmt.Errorf("backend name cannot be empty")
+	}
+	return config.SetBackendCredential(name, apiKey, baseURL, model)
+}
+
 // CreateGitCheckpoint creates a git commit with all current changes
 func (a *App) CreateGitCheckpoint(message string) error {
 	if !a.gitManager.IsGitRepo() {
@@ -345,6 +507,55 @@ func (a *App) SwitchToGitCommit(commitHash string) error {
 	return a.gitManager.CheckoutCommit(commitHash)
 }
 
+// CreateCheckpoint snapshots the working tree before a Claude Code turn runs,
+// so the turn can later be diffed or reverted independently of the user's own edits
+func (a *App) CreateCheckpoint(turnID, prompt string, touchedFiles []string) (*git.Checkpoint, error) {
+	if !a.gitManager.IsGitRepo() {
+		return nil, fmt.Errorf("not a git repository")
+	}
+	if turnID == "" {
+		return nil, fmt.Errorf("turn id cannot be empty")
+	}
+	return a.gitManager.CreateCheckpoint(turnID, prompt, touchedFiles)
+}
+
+// ListCheckpoints returns all recorded Claude-edit checkpoints, oldest first
+func (a *App) ListCheckpoints() ([]*git.Checkpoint, error) {
+	if !a.gitManager.IsGitRepo() {
+		return nil, fmt.Errorf("not a git repository")
+	}
+	return a.gitManager.ListCheckpoints()
+}
+
+// GetCheckpointDiff returns the per-file unified diff for a checkpointed Claude turn
+func (a *App) GetCheckpointDiff(id string) (map[string]string, error) {
+	if !a.gitManager.IsGitRepo() {
+		return nil, fmt.Errorf("not a git repository")
+	}
+	return a.gitManager.GetCheckpointDiff(id)
+}
+
+// RevertCheckpoint restores only the files a specific Claude turn modified,
+// leaving unrelated user edits (and later turns) untouched
+func (a *App) RevertCheckpoint(id string) error {
+	if !a.gitManager.IsGitRepo() {
+		return fmt.Errorf("not a git repository")
+	}
+	return a.gitManager.RevertCheckpoint(id)
+}
+
+// SquashCheckpointsInto collapses all recorded Claude-edit checkpoints into a
+// single real commit on the working branch
+func (a *App) SquashCheckpointsInto(message string) error {
+	if !a.gitManager.IsGitRepo() {
+		return fmt.Errorf("not a git repository")
+	}
+	if message == "" {
+		return fmt.Errorf("commit message cannot be empty")
+	}
+	return a.gitManager.SquashCheckpointsInto(message)
+}
+
 // IsGitRepository checks if the current project is a git repo
 func (a *App) IsGitRepository() bool {
 	return a.gitManager.IsGitRepo()
@@ -383,13 +594,14 @@ func (a *App) GetStatus() map[string]interface{} {
 	}
 }
 
-// StopClaudeProcessing stops the currently running Claude Code process
+// StopClaudeProcessing stops whatever turn the currently selected AI backend
+// has in flight
 func (a *App) StopClaudeProcessing() error {
-	if a.claudeManager == nil {
-		return fmt.Errorf("Claude manager not initialized")
+	if a.backend == nil {
+		return fmt.Errorf("AI backend not initialized")
 	}
 
-	return a.claudeManager.Stop()
+	return a.backend.Stop()
 }
 
 // autoStartDevServer automatically starts the development server for a project
@@ -405,6 +617,10 @@ func (a *App) autoStartDevServer(projectDir string) {
 			log.Printf("Warning: Failed to stop existing dev server: %v", err)
 		}
 	}
+	if a.devConfigWatcher != nil {
+		a.devConfigWatcher.Close()
+		a.devConfigWatcher = nil
+	}
 
 	// Create new dev server manager
 	a.devServerManager = devserver.NewManager(projectDir)
@@ -427,14 +643,108 @@ func (a *App) autoStartDevServer(projectDir string) {
 	// Store the detected port
 	a.targetPort = port
 	log.Printf("✅ Dev server started successfully on port %d", port)
+
+	// Forward supervisor state transitions to the frontend
+	go a.forwardDevServerEvents(a.devServerManager)
+
+	// Watch config files (package.json, tsconfig.json, .env*, ...) and
+	// hot-restart the dev server when one changes
+	configWatcher, err := devserver.NewWatcher(projectDir, a.devServerManager)
+	if err != nil {
+		log.Printf("Warning: Failed to start dev server config watcher: %v", err)
+		return
+	}
+	a.devConfigWatcher = configWatcher
+}
+
+// forwardDevServerEvents relays dev server lifecycle events to the frontend via
+// the Wails runtime until the manager is replaced or its event channel closes
+func (a *App) forwardDevServerEvents(manager *devserver.Manager) {
+	ch := manager.Subscribe()
+	defer manager.Unsubscribe(ch)
+
+	for evt := range ch {
+		if a.devServerManager != manager {
+			return // a newer dev server has replaced this one
+		}
+		runtime.EventsEmit(a.ctx, "devserver:state", evt)
+	}
 }
 
 // GetDevServerStatus returns the current status of the dev server
 func (a *App) GetDevServerStatus() map[string]interface{} {
-	return map[string]interface{}{
+	status := map[string]interface{}{
 		"starting": a.devServerStarting,
 		"port":     a.targetPort,
 	}
+	if a.devServerManager != nil {
+		status["state"] = a.devServerManager.State()
+	}
+	return status
+}
+
+// GetDevServerLogs returns the last n lines of captured dev server stdout/stderr
+func (a *App) GetDevServerLogs(n int) []string {
+	if a.devServerManager == nil {
+		return nil
+	}
+	return a.devServerManager.GetLogs(n)
+}
+
+// SetNetworkProfile sets the simulated network condition ("Fast 3G", "Slow 3G",
+// "Offline", or "None") applied to the asset server's proxy, and persists the choice
+func (a *App) SetNetworkProfile(name string) error {
+	profile := proxy.NetworkProfileByName(name)
+
+	if a.assetServer != nil {
+		a.assetServer.SetNetworkProfile(profile)
+	}
+
+	return config.SetNetworkProfile(config.NetworkProfileData{
+		Name:      profile.Name,
+		ReadKBps:  profile.ReadKBps,
+		WriteKBps: profile.WriteKBps,
+		LatencyMs: profile.LatencyMs,
+		LossPct:   profile.LossPct,
+	})
+}
+
+// SetCustomNetworkProfile sets a custom simulated network condition and persists it
+func (a *App) SetCustomNetworkProfile(readKBps, writeKBps, latencyMs int, lossPct float64) error {
+	profile := proxy.NetworkProfile{
+		Name:      "Custom",
+		ReadKBps:  readKBps,
+		WriteKBps: writeKBps,
+		LatencyMs: latencyMs,
+		LossPct:   lossPct,
+	}
+
+	if a.assetServer != nil {
+		a.assetServer.SetNetworkProfile(profile)
+	}
+
+	return config.SetNetworkProfile(config.NetworkProfileData{
+		Name:      profile.Name,
+		ReadKBps:  profile.ReadKBps,
+		WriteKBps: profile.WriteKBps,
+		LatencyMs: profile.LatencyMs,
+		LossPct:   profile.LossPct,
+	})
+}
+
+// GetWatchOptions returns the saved file watcher options for the current project
+func (a *App) GetWatchOptions() (config.WatchOptionsData, error) {
+	return config.GetWatchOptions(a.projectDir)
+}
+
+// SetWatchOptions persists file watcher options for the current project. They
+// take effect the next time the proxy (and its watcher) is started.
+func (a *App) SetWatchOptions(extraIgnores, extraExtensions []string, debounceMs int) error {
+	return config.SetWatchOptions(a.projectDir, config.WatchOptionsData{
+		ExtraIgnores:    extraIgnores,
+		ExtraExtensions: extraExtensions,
+		DebounceMs:      debounceMs,
+	})
 }
 
 // shutdown cleanup function
@@ -0,0 +1,40 @@
+// Package agent defines the Backend interface that every AI coding assistant
+// integration (Claude Code, OpenAI-compatible APIs, Gemini, Ollama) implements,
+// so the rest of the app can drive a turn without knowing which model is behind it.
+package agent
+
+import (
+	"context"
+
+	"github.com/thetronjohnson/layrr/pkg/claude"
+)
+
+// Event is the normalized streaming unit every backend emits. It's the same
+// type the Claude Code session already produces, so the bridge and frontend
+// don't need to change when the user switches backends.
+type Event = claude.Event
+
+// Capabilities describes what a backend supports, so callers can adapt
+// (e.g. hide "resume session" for a backend that doesn't keep server-side state).
+type Capabilities struct {
+	Streaming     bool // events arrive incrementally rather than all at once
+	ToolUse       bool // the backend can call tools and report ToolUse/ToolResult events
+	SessionResume bool // a prior conversation can be resumed by id
+}
+
+// Backend is a pluggable AI coding assistant. Exactly one turn may be in
+// flight at a time per Backend, matching the Claude Code session it replaces.
+type Backend interface {
+	// Send starts a turn for message and returns a channel of events for it.
+	// The channel is closed when the turn completes or errors.
+	Send(ctx context.Context, message string) (<-chan Event, error)
+
+	// Stop aborts whatever turn is currently in flight, if any.
+	Stop() error
+
+	// Name is the backend's stable identifier (e.g. "claude", "openai").
+	Name() string
+
+	// Capabilities reports what this backend supports.
+	Capabilities() Capabilities
+}
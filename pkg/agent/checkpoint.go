@@ -0,0 +1,106 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/thetronjohnson/layrr/pkg/claude"
+	"github.com/thetronjohnson/layrr/pkg/git"
+)
+
+// editToolNames are the Claude Code tool calls that modify the working tree.
+// A CheckpointingBackend only needs to track these to know which files a
+// turn touched.
+var editToolNames = map[string]bool{
+	"Edit":         true,
+	"Write":        true,
+	"MultiEdit":    true,
+	"NotebookEdit": true,
+}
+
+// CheckpointingBackend wraps a Backend so every turn is automatically
+// recorded as a git checkpoint: GitManager.CreateCheckpoint snapshots the
+// working tree before the turn runs, and once the turn completes its
+// Edit/Write tool_use events are used to fill in which files it actually
+// touched and GitManager.CompleteCheckpoint snapshots the working tree again,
+// so GitManager.RevertCheckpoint and GetCheckpointDiff have the turn's actual
+// before/after state to work with without a caller having to track that itself.
+type CheckpointingBackend struct {
+	Backend
+	git *git.GitManager
+}
+
+// NewCheckpointingBackend wraps backend with automatic checkpointing against gm
+func NewCheckpointingBackend(backend Backend, gm *git.GitManager) *CheckpointingBackend {
+	return &CheckpointingBackend{Backend: backend, git: gm}
+}
+
+// Send snapshots the working tree before delegating to the wrapped backend,
+// then fills in the checkpoint's touched files from the turn's Edit/Write
+// tool calls once it completes. If the project isn't a git repository, it
+// delegates straight through with no checkpoint.
+func (b *CheckpointingBackend) Send(ctx context.Context, message string) (<-chan Event, error) {
+	if !b.git.IsGitRepo() {
+		return b.Backend.Send(ctx, message)
+	}
+
+	checkpointID := fmt.Sprintf("turn-%d", time.Now().UnixNano())
+	if _, err := b.git.CreateCheckpoint(checkpointID, message, nil); err != nil {
+		return nil, fmt.Errorf("failed to create checkpoint: %w", err)
+	}
+
+	events, err := b.Backend.Send(ctx, message)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan Event, 16)
+	go b.trackAndForward(checkpointID, events, out)
+	return out, nil
+}
+
+// trackAndForward relays every event from in to out unchanged, while
+// collecting the file paths any Edit/Write tool_use event names, and once in
+// is closed (the turn has ended) records them on the checkpoint and snapshots
+// the working tree again so the checkpoint has a genuine post-turn state
+func (b *CheckpointingBackend) trackAndForward(checkpointID string, in <-chan Event, out chan<- Event) {
+	defer close(out)
+
+	touched := make(map[string]bool)
+	for evt := range in {
+		if evt.Type == claude.EventToolUse && editToolNames[evt.ToolName] {
+			if file := editedFilePath(evt.ToolInput); file != "" {
+				touched[file] = true
+			}
+		}
+		out <- evt
+	}
+
+	if len(touched) > 0 {
+		files := make([]string, 0, len(touched))
+		for file := range touched {
+			files = append(files, file)
+		}
+		if err := b.git.UpdateCheckpointFiles(checkpointID, files); err != nil {
+			fmt.Printf("[Checkpoint] Warning: failed to record touched files for %s: %v\n", checkpointID, err)
+		}
+	}
+
+	if err := b.git.CompleteCheckpoint(checkpointID); err != nil {
+		fmt.Printf("[Checkpoint] Warning: failed to record post-turn snapshot for %s: %v\n", checkpointID, err)
+	}
+}
+
+// editedFilePath extracts the target file path from an Edit/Write/MultiEdit/
+// NotebookEdit tool call's input, or "" if it isn't shaped as expected
+func editedFilePath(toolInput json.RawMessage) string {
+	var probe struct {
+		FilePath string `json:"file_path"`
+	}
+	if err := json.Unmarshal(toolInput, &probe); err != nil {
+		return ""
+	}
+	return probe.FilePath
+}
@@ -0,0 +1,138 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/thetronjohnson/layrr/pkg/claude"
+	"github.com/thetronjohnson/layrr/pkg/git"
+)
+
+// fakeEditBackend simulates a single turn that overwrites path with content,
+// reporting it as an Edit tool_use event the way a real backend's stream
+// does. relFile is reported as the edited file path, matching the
+// project-relative paths GitManager's checkpoint methods expect.
+type fakeEditBackend struct {
+	path    string
+	relFile string
+	content string
+}
+
+func (b *fakeEditBackend) Send(ctx context.Context, message string) (<-chan Event, error) {
+	if err := os.WriteFile(b.path, []byte(b.content), 0644); err != nil {
+		return nil, err
+	}
+
+	input, err := json.Marshal(map[string]string{"file_path": b.relFile})
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan Event, 1)
+	out <- Event{Type: claude.EventToolUse, ToolName: "Edit", ToolInput: input}
+	close(out)
+	return out, nil
+}
+
+func (b *fakeEditBackend) Stop() error                { return nil }
+func (b *fakeEditBackend) Name() string               { return "fake" }
+func (b *fakeEditBackend) Capabilities() Capabilities { return Capabilities{} }
+
+// newTestRepo creates a throwaway git repository with an initial commit of
+// file containing initial, and returns a GitManager rooted there
+func newTestRepo(t *testing.T, dir, file, initial string) *git.GitManager {
+	t.Helper()
+
+	runOrFail(t, dir, "init")
+	runOrFail(t, dir, "config", "user.email", "test@example.com")
+	runOrFail(t, dir, "config", "user.name", "Test")
+
+	if err := os.WriteFile(filepath.Join(dir, file), []byte(initial), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", file, err)
+	}
+	runOrFail(t, dir, "add", "-A")
+	runOrFail(t, dir, "commit", "-m", "initial")
+
+	return git.NewGitManager(dir)
+}
+
+func runOrFail(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v failed: %v (%s)", args, err, out)
+	}
+}
+
+// drain reads every event off ch until it's closed, which only happens once
+// trackAndForward has finished recording the checkpoint's touched files and
+// post-turn snapshot
+func drain(ch <-chan Event) {
+	for range ch {
+	}
+}
+
+// TestCheckpointingBackendRevertsOnlyItsOwnTurn drives two real turns through
+// CheckpointingBackend the way app.go wires it, then checks that reverting
+// the first turn undoes only what that turn changed, leaving the second
+// turn's edit intact. It exists because a checkpoint's CommitHash/ParentHash
+// pair alone (the state the tests used to fabricate by hand) doesn't capture
+// what a turn itself changed — only CompleteCheckpoint's post-turn snapshot,
+// recorded by the real Send/trackAndForward path, does.
+func TestCheckpointingBackendRevertsOnlyItsOwnTurn(t *testing.T) {
+	dir := t.TempDir()
+	const file = "app.txt"
+	gm := newTestRepo(t, dir, file, "top\nmiddle\nbottom\n")
+	path := filepath.Join(dir, file)
+
+	first := NewCheckpointingBackend(&fakeEditBackend{path: path, relFile: file, content: "top EDITED BY TURN 1\nmiddle\nbottom\n"}, gm)
+	events, err := first.Send(context.Background(), "edit the top")
+	if err != nil {
+		t.Fatalf("first Send: %v", err)
+	}
+	drain(events)
+
+	second := NewCheckpointingBackend(&fakeEditBackend{path: path, relFile: file, content: "top EDITED BY TURN 1\nmiddle\nbottom EDITED BY TURN 2\n"}, gm)
+	events, err = second.Send(context.Background(), "edit the bottom")
+	if err != nil {
+		t.Fatalf("second Send: %v", err)
+	}
+	drain(events)
+
+	checkpoints, err := gm.ListCheckpoints()
+	if err != nil {
+		t.Fatalf("ListCheckpoints: %v", err)
+	}
+	if len(checkpoints) != 2 {
+		t.Fatalf("expected 2 checkpoints, got %d", len(checkpoints))
+	}
+
+	var firstID string
+	for _, c := range checkpoints {
+		if c.Prompt == "edit the top" {
+			firstID = c.ID
+		}
+	}
+	if firstID == "" {
+		t.Fatalf("couldn't find the first turn's checkpoint among %v", checkpoints)
+	}
+
+	if err := gm.RevertCheckpoint(firstID); err != nil {
+		t.Fatalf("RevertCheckpoint: %v", err)
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading reverted file: %v", err)
+	}
+
+	want := "top\nmiddle\nbottom EDITED BY TURN 2\n"
+	if string(got) != want {
+		t.Fatalf("after reverting turn 1, got:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -0,0 +1,53 @@
+package agent
+
+import (
+	"context"
+
+	"github.com/thetronjohnson/layrr/pkg/claude"
+)
+
+// ClaudeBackend adapts the existing claude.Manager (the `claude` CLI run in
+// persistent streaming mode) to the Backend interface. It's the default
+// backend and the only one that doesn't need credentials from pkg/config,
+// since the CLI manages its own Anthropic auth.
+type ClaudeBackend struct {
+	manager *claude.Manager
+}
+
+// NewClaudeBackend wraps an already-constructed claude.Manager as a Backend
+func NewClaudeBackend(manager *claude.Manager) *ClaudeBackend {
+	return &ClaudeBackend{manager: manager}
+}
+
+// Send starts (or reuses) the manager's persistent session and forwards the message
+func (b *ClaudeBackend) Send(ctx context.Context, message string) (<-chan Event, error) {
+	session, err := b.manager.StartSession("")
+	if err != nil {
+		return nil, err
+	}
+	return session.Send(ctx, message)
+}
+
+// Stop aborts the in-flight Claude Code turn, if any. The persistent session
+// Send starts turns on has no use for Manager.Stop() (that only kills the
+// one-shot --print path's process), so this cancels the session's current
+// turn directly, falling back to Manager.Stop() for a caller still on the
+// one-shot path.
+func (b *ClaudeBackend) Stop() error {
+	if session := b.manager.CurrentSession(); session != nil {
+		if turnID := session.CurrentTurnID(); turnID != "" {
+			return session.Cancel(turnID)
+		}
+	}
+	return b.manager.Stop()
+}
+
+// Name identifies this backend as "claude"
+func (b *ClaudeBackend) Name() string {
+	return "claude"
+}
+
+// Capabilities reports the full feature set the Claude Code CLI supports
+func (b *ClaudeBackend) Capabilities() Capabilities {
+	return Capabilities{Streaming: true, ToolUse: true, SessionResume: true}
+}
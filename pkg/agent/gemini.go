@@ -0,0 +1,174 @@
+package agent
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thetronjohnson/layrr/pkg/claude"
+)
+
+// GeminiBackend talks to the Gemini API's streamGenerateContent endpoint.
+type GeminiBackend struct {
+	apiKey string
+	model  string
+	client *http.Client
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+}
+
+// NewGeminiBackend constructs a Backend for the Gemini API. model defaults to
+// gemini-1.5-flash when empty.
+func NewGeminiBackend(apiKey, model string) (*GeminiBackend, error) {
+	if apiKey == "" {
+		return nil, fmt.Errorf("gemini backend requires an API key")
+	}
+	if model == "" {
+		model = "gemini-1.5-flash"
+	}
+
+	return &GeminiBackend{
+		apiKey: apiKey,
+		model:  model,
+		client: &http.Client{Timeout: 0},
+	}, nil
+}
+
+type geminiRequest struct {
+	Contents []geminiContent `json:"contents"`
+}
+
+type geminiContent struct {
+	Role  string       `json:"role"`
+	Parts []geminiPart `json:"parts"`
+}
+
+type geminiPart struct {
+	Text string `json:"text"`
+}
+
+// geminiChunk mirrors one JSON object of the streamGenerateContent SSE body
+type geminiChunk struct {
+	Candidates []struct {
+		Content      geminiContent `json:"content"`
+		FinishReason string        `json:"finishReason"`
+	} `json:"candidates"`
+}
+
+// Send posts message to streamGenerateContent and streams the response back as Events
+func (b *GeminiBackend) Send(ctx context.Context, message string) (<-chan Event, error) {
+	turnID := fmt.Sprintf("turn-%d", time.Now().UnixNano())
+	ctx, cancel := context.WithCancel(ctx)
+
+	body, err := json.Marshal(geminiRequest{
+		Contents: []geminiContent{{Role: "user", Parts: []geminiPart{{Text: message}}}},
+	})
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	url := fmt.Sprintf(
+		"https://generativelanguage.googleapis.com/v1beta/models/%s:streamGenerateContent?alt=sse&key=%s",
+		b.model, b.apiKey)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("gemini request failed: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		cancel()
+		return nil, fmt.Errorf("gemini request failed: status %s", resp.Status)
+	}
+
+	b.mu.Lock()
+	b.cancel = cancel
+	b.mu.Unlock()
+
+	events := make(chan Event, 16)
+	go b.streamResponse(turnID, resp, events)
+
+	return events, nil
+}
+
+// streamResponse reads the SSE body and translates each `data:` line into an Event
+func (b *GeminiBackend) streamResponse(turnID string, resp *http.Response, events chan<- Event) {
+	defer close(events)
+	defer resp.Body.Close()
+
+	scanner := bufio.NewScanner(resp.Body)
+	sawFinish := false
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+		payload := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+
+		var chunk geminiChunk
+		if err := json.Unmarshal([]byte(payload), &chunk); err != nil {
+			continue // skip unparseable lines, same tolerance as the Claude CLI path
+		}
+		if len(chunk.Candidates) == 0 {
+			continue
+		}
+
+		candidate := chunk.Candidates[0]
+		for _, part := range candidate.Content.Parts {
+			if part.Text != "" {
+				events <- Event{Type: claude.EventContent, TurnID: turnID, Content: part.Text}
+			}
+		}
+		if candidate.FinishReason != "" {
+			sawFinish = true
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		events <- Event{Type: claude.EventError, TurnID: turnID, Error: err.Error()}
+		return
+	}
+	if sawFinish {
+		events <- Event{Type: claude.EventTurnComplete, TurnID: turnID}
+	}
+}
+
+// Stop cancels the in-flight request, if any
+func (b *GeminiBackend) Stop() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.cancel == nil {
+		return fmt.Errorf("no request is currently in progress")
+	}
+	b.cancel()
+	b.cancel = nil
+	return nil
+}
+
+// Name identifies this backend as "gemini"
+func (b *GeminiBackend) Name() string {
+	return "gemini"
+}
+
+// Capabilities reports that Gemini streams content but has no tool use or
+// server-side session to resume in this integration
+func (b *GeminiBackend) Capabilities() Capabilities {
+	return Capabilities{Streaming: true, ToolUse: false, SessionResume: false}
+}
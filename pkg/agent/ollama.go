@@ -0,0 +1,158 @@
+package agent
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thetronjohnson/layrr/pkg/claude"
+)
+
+// OllamaBackend talks to a local Ollama server's /api/generate endpoint, which
+// streams one JSON object per line (no API key, fully local usage).
+type OllamaBackend struct {
+	baseURL string
+	model   string
+	client  *http.Client
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+}
+
+// NewOllamaBackend constructs a Backend for a local Ollama server. baseURL
+// defaults to http://localhost:11434 and model to llama3.1 when empty.
+func NewOllamaBackend(baseURL, model string) (*OllamaBackend, error) {
+	if baseURL == "" {
+		baseURL = "http://localhost:11434"
+	}
+	if model == "" {
+		model = "llama3.1"
+	}
+
+	return &OllamaBackend{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		model:   model,
+		client:  &http.Client{Timeout: 0},
+	}, nil
+}
+
+type ollamaGenerateRequest struct {
+	Model  string `json:"model"`
+	Prompt string `json:"prompt"`
+	Stream bool   `json:"stream"`
+}
+
+// ollamaChunk mirrors one NDJSON line of the /api/generate stream response
+type ollamaChunk struct {
+	Response string `json:"response"`
+	Done     bool   `json:"done"`
+	Error    string `json:"error"`
+}
+
+// Send posts message to /api/generate and streams the response back as Events
+func (b *OllamaBackend) Send(ctx context.Context, message string) (<-chan Event, error) {
+	turnID := fmt.Sprintf("turn-%d", time.Now().UnixNano())
+	ctx, cancel := context.WithCancel(ctx)
+
+	body, err := json.Marshal(ollamaGenerateRequest{
+		Model:  b.model,
+		Prompt: message,
+		Stream: true,
+	})
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.baseURL+"/api/generate", bytes.NewReader(body))
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("ollama request failed: %w (is `ollama serve` running?)", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		cancel()
+		return nil, fmt.Errorf("ollama request failed: status %s", resp.Status)
+	}
+
+	b.mu.Lock()
+	b.cancel = cancel
+	b.mu.Unlock()
+
+	events := make(chan Event, 16)
+	go b.streamResponse(turnID, resp, events)
+
+	return events, nil
+}
+
+// streamResponse reads the NDJSON body and translates each line into an Event
+func (b *OllamaBackend) streamResponse(turnID string, resp *http.Response, events chan<- Event) {
+	defer close(events)
+	defer resp.Body.Close()
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var chunk ollamaChunk
+		if err := json.Unmarshal([]byte(line), &chunk); err != nil {
+			continue // skip unparseable lines, same tolerance as the Claude CLI path
+		}
+
+		if chunk.Error != "" {
+			events <- Event{Type: claude.EventError, TurnID: turnID, Error: chunk.Error}
+			return
+		}
+		if chunk.Response != "" {
+			events <- Event{Type: claude.EventContent, TurnID: turnID, Content: chunk.Response}
+		}
+		if chunk.Done {
+			events <- Event{Type: claude.EventTurnComplete, TurnID: turnID}
+			return
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		events <- Event{Type: claude.EventError, TurnID: turnID, Error: err.Error()}
+	}
+}
+
+// Stop cancels the in-flight request, if any
+func (b *OllamaBackend) Stop() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.cancel == nil {
+		return fmt.Errorf("no request is currently in progress")
+	}
+	b.cancel()
+	b.cancel = nil
+	return nil
+}
+
+// Name identifies this backend as "ollama"
+func (b *OllamaBackend) Name() string {
+	return "ollama"
+}
+
+// Capabilities reports that Ollama streams content but has no tool use or
+// server-side session to resume
+func (b *OllamaBackend) Capabilities() Capabilities {
+	return Capabilities{Streaming: true, ToolUse: false, SessionResume: false}
+}
@@ -0,0 +1,189 @@
+package agent
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thetronjohnson/layrr/pkg/claude"
+)
+
+// OpenAIBackend speaks the OpenAI chat completions streaming protocol, which is
+// also implemented by Groq, together.ai, and local servers like LM Studio —
+// baseURL is configurable so any of them can be pointed at.
+type OpenAIBackend struct {
+	baseURL string
+	apiKey  string
+	model   string
+	client  *http.Client
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+}
+
+// NewOpenAIBackend constructs a Backend for any OpenAI-compatible HTTP API.
+// baseURL defaults to https://api.openai.com/v1 and model to gpt-4o-mini when empty.
+func NewOpenAIBackend(baseURL, apiKey, model string) (*OpenAIBackend, error) {
+	if baseURL == "" {
+		baseURL = "https://api.openai.com/v1"
+	}
+	if model == "" {
+		model = "gpt-4o-mini"
+	}
+	if apiKey == "" {
+		return nil, fmt.Errorf("openai backend requires an API key")
+	}
+
+	return &OpenAIBackend{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		apiKey:  apiKey,
+		model:   model,
+		client:  &http.Client{Timeout: 0}, // streaming response, no overall timeout
+	}, nil
+}
+
+type openAIChatRequest struct {
+	Model    string              `json:"model"`
+	Stream   bool                `json:"stream"`
+	Messages []openAIChatMessage `json:"messages"`
+}
+
+type openAIChatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// openAIChunk mirrors one `data: {...}` line of a chat.completions stream response
+type openAIChunk struct {
+	Choices []struct {
+		Delta struct {
+			Content   string `json:"content"`
+			ToolCalls []struct {
+				Function struct {
+					Name      string `json:"name"`
+					Arguments string `json:"arguments"`
+				} `json:"function"`
+			} `json:"tool_calls"`
+		} `json:"delta"`
+		FinishReason string `json:"finish_reason"`
+	} `json:"choices"`
+}
+
+// Send posts message as a single-turn chat completion and streams the response
+// back as Events on the returned channel.
+func (b *OpenAIBackend) Send(ctx context.Context, message string) (<-chan Event, error) {
+	turnID := fmt.Sprintf("turn-%d", time.Now().UnixNano())
+	ctx, cancel := context.WithCancel(ctx)
+
+	body, err := json.Marshal(openAIChatRequest{
+		Model:    b.model,
+		Stream:   true,
+		Messages: []openAIChatMessage{{Role: "user", Content: message}},
+	})
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.baseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+b.apiKey)
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("openai request failed: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		cancel()
+		return nil, fmt.Errorf("openai request failed: status %s", resp.Status)
+	}
+
+	b.mu.Lock()
+	b.cancel = cancel
+	b.mu.Unlock()
+
+	events := make(chan Event, 16)
+	go b.streamResponse(turnID, resp, events)
+
+	return events, nil
+}
+
+// streamResponse reads the SSE body and translates each `data:` line into an Event
+func (b *OpenAIBackend) streamResponse(turnID string, resp *http.Response, events chan<- Event) {
+	defer close(events)
+	defer resp.Body.Close()
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+		payload := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		if payload == "[DONE]" {
+			events <- Event{Type: claude.EventTurnComplete, TurnID: turnID}
+			return
+		}
+
+		var chunk openAIChunk
+		if err := json.Unmarshal([]byte(payload), &chunk); err != nil {
+			continue // skip unparseable lines, same tolerance as the Claude CLI path
+		}
+		if len(chunk.Choices) == 0 {
+			continue
+		}
+
+		delta := chunk.Choices[0].Delta
+		if delta.Content != "" {
+			events <- Event{Type: claude.EventContent, TurnID: turnID, Content: delta.Content}
+		}
+		for _, call := range delta.ToolCalls {
+			events <- Event{
+				Type:      claude.EventToolUse,
+				TurnID:    turnID,
+				ToolName:  call.Function.Name,
+				ToolInput: json.RawMessage(call.Function.Arguments),
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		events <- Event{Type: claude.EventError, TurnID: turnID, Error: err.Error()}
+	}
+}
+
+// Stop cancels the in-flight request, if any
+func (b *OpenAIBackend) Stop() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.cancel == nil {
+		return fmt.Errorf("no request is currently in progress")
+	}
+	b.cancel()
+	b.cancel = nil
+	return nil
+}
+
+// Name identifies this backend as "openai"
+func (b *OpenAIBackend) Name() string {
+	return "openai"
+}
+
+// Capabilities reports that OpenAI-compatible chat completions stream content
+// and tool calls, but have no server-side session to resume
+func (b *OpenAIBackend) Capabilities() Capabilities {
+	return Capabilities{Streaming: true, ToolUse: true, SessionResume: false}
+}
@@ -0,0 +1,39 @@
+package agent
+
+import (
+	"fmt"
+
+	"github.com/thetronjohnson/layrr/pkg/claude"
+	"github.com/thetronjohnson/layrr/pkg/config"
+)
+
+// Built-in backend identifiers, used for both config persistence and App.ListBackends
+const (
+	BackendClaude = "claude"
+	BackendOpenAI = "openai"
+	BackendGemini = "gemini"
+	BackendOllama = "ollama"
+)
+
+// Names returns the built-in backend identifiers in display order
+func Names() []string {
+	return []string{BackendClaude, BackendOpenAI, BackendGemini, BackendOllama}
+}
+
+// New constructs the named backend from persisted configuration. claudeManager
+// is reused for BackendClaude so the existing CLI session lifecycle is unchanged;
+// an empty name falls back to BackendClaude.
+func New(name string, claudeManager *claude.Manager, cfg config.BackendConfig) (Backend, error) {
+	switch name {
+	case BackendClaude, "":
+		return NewClaudeBackend(claudeManager), nil
+	case BackendOpenAI:
+		return NewOpenAIBackend(cfg.BaseURLs[BackendOpenAI], cfg.APIKeys[BackendOpenAI], cfg.Models[BackendOpenAI])
+	case BackendGemini:
+		return NewGeminiBackend(cfg.APIKeys[BackendGemini], cfg.Models[BackendGemini])
+	case BackendOllama:
+		return NewOllamaBackend(cfg.BaseURLs[BackendOllama], cfg.Models[BackendOllama])
+	default:
+		return nil, fmt.Errorf("unknown backend %q", name)
+	}
+}
@@ -0,0 +1,262 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"sort"
+)
+
+// signals is what a detector's Dependencies/ConfigGlobs/Dirs/FileSuffixes
+// are matched against — the project's combined package.json dependency set
+// plus its filesystem
+type signals struct {
+	allDeps    map[string]bool
+	projectDir string
+}
+
+func (s signals) hasAnyDep(deps []string) bool {
+	for _, dep := range deps {
+		if s.allDeps[dep] {
+			return true
+		}
+	}
+	return false
+}
+
+func (s signals) hasAnyConfigFile(globs []string) bool {
+	for _, pattern := range globs {
+		matches, err := filepath.Glob(filepath.Join(s.projectDir, pattern))
+		if err == nil && len(matches) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (s signals) hasAnyDir(dirs []string) bool {
+	for _, dir := range dirs {
+		if dirExists(filepath.Join(s.projectDir, dir)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s signals) hasAnySuffix(suffixes []string) bool {
+	for _, suffix := range suffixes {
+		if hasFileWithSuffix(s.projectDir, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// extJSXFamily marks a FrameworkDetector whose file extension isn't fixed:
+// it's ".tsx"/".jsx" depending on ProjectContext.TypeScript, same as the
+// base React convention it's built on
+const extJSXFamily = "jsx-family"
+
+// FrameworkDetector is one pluggable signal set AnalyzeProject checks to
+// decide ProjectContext.Framework. Detectors run in ascending Priority
+// order (ties keep registration order) and the first match wins, so
+// metaframeworks that also depend on their base library (e.g. SvelteKit
+// also depends on "svelte") must be given a lower Priority than it.
+type FrameworkDetector struct {
+	Name     string
+	Priority int
+
+	Dependencies []string // matches if package.json declares any of these
+	ConfigGlobs  []string // matches if any glob (relative to the project root) resolves
+	Dirs         []string // matches if any of these directories exists
+
+	// Extension is the file extension GetFileExtension returns for this
+	// framework, or extJSXFamily for frameworks that follow React's
+	// TypeScript-dependent .tsx/.jsx convention. Frameworks that don't set
+	// this fall back to ProjectContext's generic .ts/.js default.
+	Extension string
+
+	// Apply runs after this detector wins, to populate any extra
+	// ProjectContext fields the framework needs beyond Framework itself
+	// (e.g. NextJSRouter, Bundler, RouterFlavor)
+	Apply func(ctx *ProjectContext, projectDir string)
+}
+
+func (d FrameworkDetector) matches(s signals) bool {
+	return s.hasAnyDep(d.Dependencies) || s.hasAnyConfigFile(d.ConfigGlobs) || s.hasAnyDir(d.Dirs)
+}
+
+// DependencyDetector is the simpler signal set used for styling approaches
+// and UI component libraries, which never need extra directory signals or
+// ProjectContext side effects — dependency and config-file presence (plus,
+// for styling, a file-suffix convention like ".module.css") are enough.
+type DependencyDetector struct {
+	Name         string
+	Priority     int
+	Dependencies []string
+	ConfigGlobs  []string
+	FileSuffixes []string
+}
+
+func (d DependencyDetector) matches(s signals) bool {
+	return s.hasAnyDep(d.Dependencies) || s.hasAnyConfigFile(d.ConfigGlobs) || s.hasAnySuffix(d.FileSuffixes)
+}
+
+// frameworkDetectors holds the built-in framework signal set; metaframeworks
+// are given a lower Priority than the base library they're built on so they
+// win the tie (e.g. SvelteKit's "@sveltejs/kit" dependency also pulls in "svelte")
+var frameworkDetectors = []FrameworkDetector{
+	{
+		Name: "nextjs", Priority: 10,
+		Dependencies: []string{"next"},
+		ConfigGlobs:  []string{"next.config.*"},
+		Extension:    extJSXFamily,
+		Apply: func(ctx *ProjectContext, projectDir string) {
+			ctx.NextJSRouter = detectNextJSRouter(projectDir)
+		},
+	},
+	{
+		Name: "remix", Priority: 15,
+		Dependencies: []string{"@remix-run/react", "@remix-run/node", "@remix-run/serve"},
+		ConfigGlobs:  []string{"remix.config.*"},
+		Extension:    extJSXFamily,
+		Apply: func(ctx *ProjectContext, projectDir string) {
+			if dirExists(filepath.Join(projectDir, "app", "routes")) {
+				ctx.RouterFlavor = "remix-app"
+			} else {
+				ctx.RouterFlavor = "remix-classic"
+			}
+		},
+	},
+	{
+		Name: "nuxt", Priority: 20,
+		Dependencies: []string{"nuxt", "nuxt3"},
+		ConfigGlobs:  []string{"nuxt.config.*"},
+		Extension:    ".vue",
+		Apply:        func(ctx *ProjectContext, projectDir string) { ctx.Bundler = "vite" },
+	},
+	{
+		Name: "astro", Priority: 25,
+		Dependencies: []string{"astro"},
+		ConfigGlobs:  []string{"astro.config.*"},
+		Extension:    ".astro",
+	},
+	{
+		Name: "sveltekit", Priority: 30,
+		Dependencies: []string{"@sveltejs/kit"},
+		ConfigGlobs:  []string{"svelte.config.*"},
+		Extension:    ".svelte",
+		Apply:        func(ctx *ProjectContext, projectDir string) { ctx.Bundler = "vite" },
+	},
+	{
+		Name: "solidstart", Priority: 35,
+		Dependencies: []string{"@solidjs/start", "solid-start"},
+		Extension:    extJSXFamily,
+		Apply:        func(ctx *ProjectContext, projectDir string) { ctx.Bundler = "vite" },
+	},
+	{
+		Name: "qwik", Priority: 40,
+		Dependencies: []string{"@builder.io/qwik", "@builder.io/qwik-city"},
+		Extension:    extJSXFamily,
+		Apply:        func(ctx *ProjectContext, projectDir string) { ctx.Bundler = "vite" },
+	},
+	{
+		Name: "react", Priority: 50,
+		Dependencies: []string{"react", "react-dom"},
+		Extension:    extJSXFamily,
+	},
+	{
+		Name: "vue", Priority: 50,
+		Dependencies: []string{"vue"},
+		Extension:    ".vue",
+	},
+	{
+		Name: "svelte", Priority: 50,
+		Dependencies: []string{"svelte"},
+		Extension:    ".svelte",
+	},
+	{
+		Name: "angular", Priority: 50,
+		Dependencies: []string{"@angular/core"},
+		Extension:    ".component.ts",
+	},
+}
+
+// stylingDetectors holds the built-in styling-approach signal set. Tailwind
+// and Sass/PostCSS are all commonly layered together, so order here is the
+// tie-break precedence, most-specific first; "css-modules" has no
+// dependency of its own so it's ordered last as a pure file-suffix fallback.
+var stylingDetectors = []DependencyDetector{
+	{Name: "tailwind", Priority: 10, Dependencies: []string{"tailwindcss"}, ConfigGlobs: []string{"tailwind.config.*"}},
+	{Name: "vanilla-extract", Priority: 15, Dependencies: []string{"@vanilla-extract/css"}},
+	{Name: "unocss", Priority: 20, Dependencies: []string{"unocss"}, ConfigGlobs: []string{"uno.config.*"}},
+	{Name: "panda-css", Priority: 25, Dependencies: []string{"@pandacss/dev"}, ConfigGlobs: []string{"panda.config.*"}},
+	{Name: "styled-components", Priority: 30, Dependencies: []string{"styled-components"}},
+	{Name: "emotion", Priority: 35, Dependencies: []string{"@emotion/react", "@emotion/styled"}},
+	{Name: "sass", Priority: 40, Dependencies: []string{"sass", "node-sass"}, FileSuffixes: []string{".scss", ".sass"}},
+	{Name: "postcss", Priority: 45, Dependencies: []string{"postcss"}, ConfigGlobs: []string{"postcss.config.*"}},
+	{Name: "css-modules", Priority: 50, FileSuffixes: []string{".module.css", ".module.scss"}},
+}
+
+// uiLibraryDetectors holds the built-in component-library signal set. This
+// is orthogonal to styling — a project can be both "tailwind" and "chakra".
+var uiLibraryDetectors = []DependencyDetector{
+	{Name: "chakra", Priority: 10, Dependencies: []string{"@chakra-ui/react"}},
+	{Name: "mantine", Priority: 20, Dependencies: []string{"@mantine/core"}},
+}
+
+// Detectors returns the registered framework detectors, in the priority
+// order AnalyzeProject runs them
+func Detectors() []FrameworkDetector {
+	return sortedFrameworkDetectors()
+}
+
+// RegisterDetector adds a custom framework detector. Give it a Priority
+// lower than any built-in detector it needs to win a tie against (e.g. a
+// metaframework built on a library this package already detects).
+func RegisterDetector(d FrameworkDetector) {
+	frameworkDetectors = append(frameworkDetectors, d)
+}
+
+// StylingDetectors returns the registered styling-approach detectors, in
+// the priority order AnalyzeProject runs them
+func StylingDetectors() []DependencyDetector {
+	return sortedDependencyDetectors(stylingDetectors)
+}
+
+// RegisterStylingDetector adds a custom styling-approach detector
+func RegisterStylingDetector(d DependencyDetector) {
+	stylingDetectors = append(stylingDetectors, d)
+}
+
+// UILibraryDetectors returns the registered UI component-library detectors,
+// in the priority order AnalyzeProject runs them
+func UILibraryDetectors() []DependencyDetector {
+	return sortedDependencyDetectors(uiLibraryDetectors)
+}
+
+// RegisterUILibraryDetector adds a custom UI component-library detector
+func RegisterUILibraryDetector(d DependencyDetector) {
+	uiLibraryDetectors = append(uiLibraryDetectors, d)
+}
+
+func sortedFrameworkDetectors() []FrameworkDetector {
+	sorted := append([]FrameworkDetector{}, frameworkDetectors...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Priority < sorted[j].Priority })
+	return sorted
+}
+
+func sortedDependencyDetectors(detectors []DependencyDetector) []DependencyDetector {
+	sorted := append([]DependencyDetector{}, detectors...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Priority < sorted[j].Priority })
+	return sorted
+}
+
+// extensionForFramework looks up the Extension a registered FrameworkDetector
+// declared for name, if any
+func extensionForFramework(name string) (string, bool) {
+	for _, d := range frameworkDetectors {
+		if d.Name == name && d.Extension != "" {
+			return d.Extension, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,146 @@
+package analyzer
+
+import (
+	"net/url"
+	"path/filepath"
+	"strings"
+)
+
+// FrameworkAdapter abstracts the framework-specific conventions the image
+// attachment flow depends on: where static assets are served from, which
+// source files to search when rewriting a reference to one, and how to
+// unwrap a framework's image-optimizer URL back to the original asset path.
+type FrameworkAdapter interface {
+	// PublicDir returns the directory static assets are served from, joined onto projectDir
+	PublicDir(projectDir string) string
+	// SourceExtensions lists the file extensions searched when rewriting an image reference
+	SourceExtensions() []string
+	// IgnoreDirs lists directory names to skip while walking the project
+	IgnoreDirs() []string
+	// ExtractOriginalAssetURL unwraps assetURL if it's this framework's image-optimizer
+	// URL scheme, returning it unchanged otherwise
+	ExtractOriginalAssetURL(assetURL string) string
+}
+
+// jsSourceExtensions covers every source file type an adapter might need to
+// search for an image reference across the frameworks registered below
+var jsSourceExtensions = []string{".tsx", ".ts", ".jsx", ".js", ".vue", ".svelte", ".astro"}
+
+// baseIgnoreDirs is shared by every adapter; each adds its own build output dir on top
+var baseIgnoreDirs = []string{"node_modules", ".git", "dist", "build"}
+
+// staticAssetAdapter is the shared behavior for frameworks that serve assets
+// unmodified from a single static directory with no image-optimizer URL to
+// unwrap (Vite's React/Vue/Svelte templates, plain HTML, Angular)
+type staticAssetAdapter struct {
+	publicDirName string
+	buildDirs     []string
+}
+
+func (a staticAssetAdapter) PublicDir(projectDir string) string {
+	return filepath.Join(projectDir, a.publicDirName)
+}
+
+func (a staticAssetAdapter) SourceExtensions() []string { return jsSourceExtensions }
+
+func (a staticAssetAdapter) IgnoreDirs() []string {
+	return append(append([]string{}, baseIgnoreDirs...), a.buildDirs...)
+}
+
+func (a staticAssetAdapter) ExtractOriginalAssetURL(assetURL string) string { return assetURL }
+
+// nextJSAdapter unwraps Next.js's `/_next/image?url=...` image-optimizer URL,
+// e.g. "/_next/image?url=%2Favatar.webp&w=3840&q=75" -> "/avatar.webp"
+type nextJSAdapter struct{ staticAssetAdapter }
+
+func (nextJSAdapter) ExtractOriginalAssetURL(assetURL string) string {
+	return extractURLQueryParam(assetURL, "/_next/image", "url")
+}
+
+// astroAdapter unwraps Astro's `/_image?href=...` image-optimizer URL,
+// e.g. "/_image?href=%2Fhero.jpg&w=1200&f=webp" -> "/hero.jpg"
+type astroAdapter struct{ staticAssetAdapter }
+
+func (astroAdapter) ExtractOriginalAssetURL(assetURL string) string {
+	return extractURLQueryParam(assetURL, "/_image", "href")
+}
+
+// nuxtAdapter unwraps Nuxt's IPX image-optimizer URL, which encodes modifiers
+// and the original path as sibling segments rather than query parameters,
+// e.g. "/_ipx/w_300/%2Fimages%2Fhero.jpg" -> "/images/hero.jpg"
+type nuxtAdapter struct{ staticAssetAdapter }
+
+func (nuxtAdapter) ExtractOriginalAssetURL(assetURL string) string {
+	const prefix = "/_ipx/"
+	if !strings.HasPrefix(assetURL, prefix) {
+		return assetURL
+	}
+
+	rest := strings.TrimPrefix(assetURL, prefix)
+	parts := strings.SplitN(rest, "/", 2)
+	if len(parts) != 2 {
+		return assetURL
+	}
+
+	decoded, err := url.QueryUnescape(parts[1])
+	if err != nil {
+		return parts[1]
+	}
+	return decoded
+}
+
+// svelteKitAdapter serves assets from SvelteKit's static/ directory (mapped
+// to the site root at build time); SvelteKit has no bundled image optimizer
+// to unwrap, so it reuses staticAssetAdapter's pass-through URL handling
+type svelteKitAdapter struct{ staticAssetAdapter }
+
+// extractURLQueryParam pulls a single URL-encoded query parameter's value out
+// of assetURL if it contains marker, returning assetURL unchanged otherwise
+func extractURLQueryParam(assetURL, marker, param string) string {
+	if !strings.Contains(assetURL, marker) {
+		return assetURL
+	}
+
+	needle := param + "="
+	idx := strings.Index(assetURL, needle)
+	if idx == -1 {
+		return assetURL
+	}
+
+	value := assetURL[idx+len(needle):]
+	if amp := strings.Index(value, "&"); amp != -1 {
+		value = value[:amp]
+	}
+
+	decoded, err := url.QueryUnescape(value)
+	if err != nil {
+		return value
+	}
+	return decoded
+}
+
+// adaptersByFramework maps ProjectContext.Framework to its FrameworkAdapter
+var adaptersByFramework = map[string]FrameworkAdapter{
+	"nextjs":    nextJSAdapter{staticAssetAdapter{publicDirName: "public", buildDirs: []string{".next"}}},
+	"nuxt":      nuxtAdapter{staticAssetAdapter{publicDirName: "public", buildDirs: []string{".nuxt", ".output"}}},
+	"astro":     astroAdapter{staticAssetAdapter{publicDirName: "public", buildDirs: []string{".astro"}}},
+	"sveltekit": svelteKitAdapter{staticAssetAdapter{publicDirName: "static", buildDirs: []string{".svelte-kit"}}},
+	"react":     staticAssetAdapter{publicDirName: "public"},
+	"vue":       staticAssetAdapter{publicDirName: "public"},
+	"svelte":    staticAssetAdapter{publicDirName: "public"},
+	"angular":   staticAssetAdapter{publicDirName: "src/assets"},
+	"html":      staticAssetAdapter{publicDirName: "."},
+}
+
+// defaultAdapter covers any framework without a specific entry in adaptersByFramework
+var defaultAdapter = staticAssetAdapter{publicDirName: "public"}
+
+// Adapter returns the FrameworkAdapter registered for ctx.Framework, falling
+// back to a generic public-dir adapter for frameworks without a special
+// image-optimizer URL to unwrap
+func (ctx *ProjectContext) Adapter() FrameworkAdapter {
+	if adapter, ok := adaptersByFramework[ctx.Framework]; ok {
+		return adapter
+	}
+	return defaultAdapter
+}
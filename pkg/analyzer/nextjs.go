@@ -1,17 +1,35 @@
 package analyzer
 
 import (
+	"os"
 	"path/filepath"
+	"sync"
 )
 
-// NextJSFileLocator helps locate Next.js-specific files based on router type
+// NextJSFileLocator helps locate Next.js-specific files based on router type.
+// It builds an in-memory route table from the project's app/ (or pages/)
+// tree the first time it's needed and reuses it on every subsequent lookup;
+// call Refresh after the project's files change on disk.
+//
+// ProjectDir must be the Next.js package's own root — the directory its
+// app/ or pages/ tree and package.json live in. In a monorepo this is a
+// specific package (e.g. "apps/web"), not the workspace root: resolve it
+// via DetectWorkspace and either a Workspace.Packages entry's RelPath or
+// Workspace.PrimaryPackage when the caller hasn't named one.
 type NextJSFileLocator struct {
 	ProjectDir string
 	RouterType string
 	TypeScript bool
+
+	mu             sync.Mutex
+	appRouteTree   *routeNode
+	pagesRouteTree *routeNode
+	built          bool
 }
 
-// NewNextJSFileLocator creates a new Next.JS file locator
+// NewNextJSFileLocator creates a new Next.JS file locator rooted at
+// projectDir, the Next.js package's own directory (see NextJSFileLocator's
+// doc comment for monorepo usage)
 func NewNextJSFileLocator(projectDir string, routerType string, typescript bool) *NextJSFileLocator {
 	return &NextJSFileLocator{
 		ProjectDir: projectDir,
@@ -20,114 +38,134 @@ func NewNextJSFileLocator(projectDir string, routerType string, typescript bool)
 	}
 }
 
-// FindPageFile finds the page file for a given route
-// Examples:
-//   - "/" returns "app/page.tsx" or "pages/index.tsx"
-//   - "/about" returns "app/about/page.tsx" or "pages/about.tsx"
-func (n *NextJSFileLocator) FindPageFile(route string) string {
-	ext := ".js"
-	if n.TypeScript {
-		ext = ".tsx"
+// Refresh discards the cached route table so the next lookup rescans the
+// project's app/ or pages/ tree from disk
+func (n *NextJSFileLocator) Refresh() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.appRouteTree = nil
+	n.pagesRouteTree = nil
+	n.built = false
+}
+
+// ensureRouteTables builds the App and Pages Router route tables on first
+// use (or after Refresh), preferring src/app and src/pages over app and
+// pages the same way the rest of this locator does
+func (n *NextJSFileLocator) ensureRouteTables() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.built {
+		return
 	}
 
-	if n.RouterType == "app" {
-		// App Router: routes are directories with page files
-		// Check both root app/ and src/app/
-		if route == "/" || route == "" {
-			// Check src/app/page.tsx first, then app/page.tsx
-			srcPath := filepath.Join(n.ProjectDir, "src", "app", "page"+ext)
-			if fileExists(srcPath) {
-				return srcPath
-			}
-			return filepath.Join(n.ProjectDir, "app", "page"+ext)
-		}
+	if appDir := n.preferredRoot("app"); appDir != "" {
+		n.appRouteTree = n.buildAppRouteTree(appDir)
+	}
+	if pagesDir := n.preferredRoot("pages"); pagesDir != "" {
+		n.pagesRouteTree = n.buildPagesRouteTree(pagesDir)
+	}
+	n.built = true
+}
 
-		// Remove leading slash
-		route = filepath.Clean(route)
+// preferredRoot returns src/<name> if it exists, else <name> if it exists,
+// else ""
+func (n *NextJSFileLocator) preferredRoot(name string) string {
+	srcPath := filepath.Join(n.ProjectDir, "src", name)
+	if dirExists(srcPath) {
+		return srcPath
+	}
+	path := filepath.Join(n.ProjectDir, name)
+	if dirExists(path) {
+		return path
+	}
+	return ""
+}
 
-		// Check src/app/route/page.tsx first
-		srcPath := filepath.Join(n.ProjectDir, "src", "app", route, "page"+ext)
-		if fileExists(srcPath) {
-			return srcPath
-		}
+func (n *NextJSFileLocator) fileExt() string {
+	if n.TypeScript {
+		return ".tsx"
+	}
+	return ".js"
+}
+
+// FindPageFile resolves route against the cached route table, understanding
+// static segments, route groups (marketing), dynamic segments [id],
+// catch-all [...rest] and optional catch-all [[...rest]] segments, and
+// parallel route slots @modal. It returns the matched page file, bound
+// params, the layout/loading/error/not-found chain from root to leaf, and
+// any parallel slot files rendered alongside the match.
+//
+// Examples:
+//   - "/" resolves to "app/page.tsx" or "pages/index.tsx"
+//   - "/blog/hello-world" against app/blog/[slug]/page.tsx binds {slug: "hello-world"}
+func (n *NextJSFileLocator) FindPageFile(route string) (RouteMatch, bool) {
+	n.ensureRouteTables()
+	segments := splitRoute(route)
 
-		// Then app/route/page.tsx
-		return filepath.Join(n.ProjectDir, "app", route, "page"+ext)
+	if n.RouterType == "app" {
+		return n.resolveAppRoute(segments)
 	}
+	return n.resolvePagesRoute(segments)
+}
 
-	// Pages Router: routes are files
-	if route == "/" || route == "" {
-		// Check src/pages/index.tsx first
-		srcPath := filepath.Join(n.ProjectDir, "src", "pages", "index"+ext)
-		if fileExists(srcPath) {
-			return srcPath
-		}
-		return filepath.Join(n.ProjectDir, "pages", "index"+ext)
+func (n *NextJSFileLocator) resolveAppRoute(segments []string) (RouteMatch, bool) {
+	if n.appRouteTree == nil {
+		return RouteMatch{}, false
 	}
 
-	// Remove leading slash for file name
-	route = filepath.Clean(route)
+	params := make(map[string]string)
+	leaf, ok := matchRoute(n.appRouteTree, segments, params)
+	if !ok || leaf.pageFile == "" {
+		return RouteMatch{}, false
+	}
 
-	// Check src/pages first
-	srcPath := filepath.Join(n.ProjectDir, "src", "pages", route+ext)
-	if fileExists(srcPath) {
-		return srcPath
+	layouts, loading, errors, notFound, slots := collectChain(n.appRouteTree, segments)
+	return RouteMatch{
+		PageFile:      leaf.pageFile,
+		Params:        params,
+		Layouts:       layouts,
+		LoadingFiles:  loading,
+		ErrorFiles:    errors,
+		NotFoundFiles: notFound,
+		ParallelSlots: slots,
+	}, true
+}
+
+func (n *NextJSFileLocator) resolvePagesRoute(segments []string) (RouteMatch, bool) {
+	if n.pagesRouteTree == nil {
+		return RouteMatch{}, false
+	}
+
+	params := make(map[string]string)
+	leaf, ok := matchRoute(n.pagesRouteTree, segments, params)
+	if !ok || leaf.pageFile == "" {
+		return RouteMatch{}, false
 	}
 
-	return filepath.Join(n.ProjectDir, "pages", route+ext)
+	return RouteMatch{
+		PageFile: leaf.pageFile,
+		Params:   params,
+		Layouts:  []string{n.FindLayoutFile("/")},
+	}, true
 }
 
-// FindLayoutFile finds the layout file for a given route
-// App Router: Walks up from route to find nearest layout.tsx
-// Pages Router: Returns _app.tsx
+// FindLayoutFile finds the layout file for a given route: the nearest
+// (leaf-most) layout.tsx for the App Router, or _app.tsx for the Pages
+// Router
 func (n *NextJSFileLocator) FindLayoutFile(route string) string {
-	ext := ".js"
-	if n.TypeScript {
-		ext = ".tsx"
-	}
+	ext := n.fileExt()
 
 	if n.RouterType == "app" {
-		// For App Router, find the nearest layout.tsx
-		// Start from the route and walk up
-		if route == "/" || route == "" {
-			// Root layout
-			srcPath := filepath.Join(n.ProjectDir, "src", "app", "layout"+ext)
-			if fileExists(srcPath) {
-				return srcPath
+		n.ensureRouteTables()
+		if n.appRouteTree != nil {
+			layouts, _, _, _, _ := collectChain(n.appRouteTree, splitRoute(route))
+			if len(layouts) > 0 {
+				return layouts[len(layouts)-1]
 			}
-			return filepath.Join(n.ProjectDir, "app", "layout"+ext)
 		}
-
-		// Walk up the route path to find layout
-		route = filepath.Clean(route)
-		parts := filepath.SplitList(route)
-
-		// Try each level from deepest to root
-		for i := len(parts); i >= 0; i-- {
-			var layoutPath string
-			if i == 0 {
-				// Root level
-				layoutPath = filepath.Join(n.ProjectDir, "src", "app", "layout"+ext)
-				if fileExists(layoutPath) {
-					return layoutPath
-				}
-				layoutPath = filepath.Join(n.ProjectDir, "app", "layout"+ext)
-			} else {
-				// Intermediate level
-				subPath := filepath.Join(parts[:i]...)
-				layoutPath = filepath.Join(n.ProjectDir, "src", "app", subPath, "layout"+ext)
-				if fileExists(layoutPath) {
-					return layoutPath
-				}
-				layoutPath = filepath.Join(n.ProjectDir, "app", subPath, "layout"+ext)
-			}
-
-			if fileExists(layoutPath) {
-				return layoutPath
-			}
+		if appDir := n.preferredRoot("app"); appDir != "" {
+			return filepath.Join(appDir, "layout"+ext)
 		}
-
-		// Default to root layout
 		return filepath.Join(n.ProjectDir, "app", "layout"+ext)
 	}
 
@@ -146,10 +184,19 @@ func (n *NextJSFileLocator) GetRootLayoutFile() string {
 
 // GetHomePageFile returns the homepage file path
 func (n *NextJSFileLocator) GetHomePageFile() string {
-	return n.FindPageFile("/")
+	if match, ok := n.FindPageFile("/"); ok {
+		return match.PageFile
+	}
+	return ""
 }
 
 // fileExists checks if a file exists
 func fileExists(path string) bool {
 	return hasFile(filepath.Dir(path), filepath.Base(path))
 }
+
+// dirExists reports whether path exists and is a directory
+func dirExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
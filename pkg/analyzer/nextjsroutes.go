@@ -0,0 +1,329 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// segmentKind distinguishes how a routeNode's directory (or Pages Router
+// filename) segment matches against a URL path segment
+type segmentKind int
+
+const (
+	staticSegment           segmentKind = iota // e.g. "about"
+	dynamicSegment                             // "[id]" — matches exactly one segment
+	catchAllSegment                            // "[...rest]" — matches one or more segments
+	optionalCatchAllSegment                    // "[[...rest]]" — matches zero or more segments
+)
+
+// routeNode is one directory in the App (or Pages) Router tree. Route groups
+// "(marketing)" are transparent: they never become a routeNode themselves,
+// their children are merged straight into their parent.
+type routeNode struct {
+	children     map[string]*routeNode // static children, keyed by segment name
+	dynamic      *routeNode            // the single dynamic/catch-all child, if any
+	dynamicKind  segmentKind
+	dynamicParam string // the bracket's bound name, e.g. "id" or "rest"
+
+	pageFile     string
+	layoutFile   string
+	loadingFile  string
+	errorFile    string
+	notFoundFile string
+
+	// parallelSlots holds each @slot directory's own subtree, keyed by slot
+	// name without the "@". Slots render alongside whatever the enclosing
+	// layout resolves to, so matching walks them independently of children.
+	parallelSlots map[string]*routeNode
+}
+
+func newRouteNode() *routeNode {
+	return &routeNode{children: make(map[string]*routeNode)}
+}
+
+// RouteMatch is the result of resolving a URL path against a NextJSFileLocator's
+// route table: the page file to edit, its bound dynamic parameters, the
+// layout chain from root to leaf, any special files found along that chain,
+// and the parallel slot files rendered alongside it.
+type RouteMatch struct {
+	PageFile      string
+	Params        map[string]string
+	Layouts       []string // root to leaf
+	LoadingFiles  []string // root to leaf
+	ErrorFiles    []string // root to leaf
+	NotFoundFiles []string // root to leaf
+	ParallelSlots []string
+}
+
+// buildAppRouteTree scans dir (an app/ or src/app/ root) into a routeNode
+// tree, resolving route groups and parallel slots as it goes
+func (n *NextJSFileLocator) buildAppRouteTree(dir string) *routeNode {
+	node := newRouteNode()
+	n.populateAppRouteNode(node, dir)
+	return node
+}
+
+// populateAppRouteNode fills node with the special files and children found
+// directly under dir, recursing transparently through route groups so their
+// contents land on node itself rather than a child
+func (n *NextJSFileLocator) populateAppRouteNode(node *routeNode, dir string) {
+	ext := n.fileExt()
+	if node.pageFile == "" {
+		node.pageFile = firstExisting(dir, "page", ext)
+	}
+	if node.layoutFile == "" {
+		node.layoutFile = firstExisting(dir, "layout", ext)
+	}
+	if node.loadingFile == "" {
+		node.loadingFile = firstExisting(dir, "loading", ext)
+	}
+	if node.errorFile == "" {
+		node.errorFile = firstExisting(dir, "error", ext)
+	}
+	if node.notFoundFile == "" {
+		node.notFoundFile = firstExisting(dir, "not-found", ext)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		name := entry.Name()
+		childDir := filepath.Join(dir, name)
+
+		switch {
+		case isRouteGroup(name):
+			// Transparent: merge the group's contents into node itself
+			n.populateAppRouteNode(node, childDir)
+
+		case strings.HasPrefix(name, "@"):
+			slotName := strings.TrimPrefix(name, "@")
+			if node.parallelSlots == nil {
+				node.parallelSlots = make(map[string]*routeNode)
+			}
+			node.parallelSlots[slotName] = n.buildAppRouteTree(childDir)
+
+		case isDynamicSegmentName(name):
+			kind, param := parseDynamicSegment(name)
+			child := newRouteNode()
+			n.populateAppRouteNode(child, childDir)
+			node.dynamic = child
+			node.dynamicKind = kind
+			node.dynamicParam = param
+
+		default:
+			child := newRouteNode()
+			n.populateAppRouteNode(child, childDir)
+			node.children[name] = child
+		}
+	}
+}
+
+// buildPagesRouteTree scans dir (a pages/ or src/pages/ root) into a
+// routeNode tree. Unlike the App Router, each file (not just index files) is
+// itself a route leaf, and dynamic segments can appear in filenames as well
+// as directory names — e.g. "blog/[slug].tsx" or "blog/[slug]/index.tsx".
+func (n *NextJSFileLocator) buildPagesRouteTree(dir string) *routeNode {
+	node := newRouteNode()
+	n.populatePagesRouteNode(node, dir)
+	return node
+}
+
+func (n *NextJSFileLocator) populatePagesRouteNode(node *routeNode, dir string) {
+	ext := n.fileExt()
+	if node.pageFile == "" {
+		node.pageFile = firstExisting(dir, "index", ext)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if strings.HasPrefix(name, "_") {
+			continue // _app, _document, _error, etc. aren't routes
+		}
+
+		// A segment can have both a file and a same-named directory (e.g.
+		// "[slug].tsx" for /blog/:slug and "[slug]/comments.tsx" for
+		// /blog/:slug/comments), so whichever of the two is seen first must
+		// merge onto the other's node rather than replace it outright.
+		if entry.IsDir() {
+			childDir := filepath.Join(dir, name)
+			if isDynamicSegmentName(name) {
+				kind, param := parseDynamicSegment(name)
+				if node.dynamic == nil {
+					node.dynamic = newRouteNode()
+				}
+				n.populatePagesRouteNode(node.dynamic, childDir)
+				node.dynamicKind = kind
+				node.dynamicParam = param
+			} else {
+				if node.children[name] == nil {
+					node.children[name] = newRouteNode()
+				}
+				n.populatePagesRouteNode(node.children[name], childDir)
+			}
+			continue
+		}
+
+		stem, fileExt := splitExt(name)
+		if fileExt != ext || stem == "index" {
+			continue
+		}
+
+		filePath := filepath.Join(dir, name)
+		if isDynamicSegmentName(stem) {
+			kind, param := parseDynamicSegment(stem)
+			if node.dynamic == nil {
+				node.dynamic = newRouteNode()
+			}
+			node.dynamic.pageFile = filePath
+			node.dynamicKind = kind
+			node.dynamicParam = param
+		} else {
+			if node.children[stem] == nil {
+				node.children[stem] = newRouteNode()
+			}
+			node.children[stem].pageFile = filePath
+		}
+	}
+}
+
+// splitExt splits a filename into its stem and extension, e.g.
+// "[slug].tsx" -> "[slug]", ".tsx"
+func splitExt(name string) (stem, ext string) {
+	ext = filepath.Ext(name)
+	return strings.TrimSuffix(name, ext), ext
+}
+
+// isRouteGroup reports whether name is a route group directory, e.g. "(marketing)"
+func isRouteGroup(name string) bool {
+	return strings.HasPrefix(name, "(") && strings.HasSuffix(name, ")")
+}
+
+// isDynamicSegmentName reports whether name is a bracketed dynamic segment:
+// "[id]", "[...rest]", or "[[...rest]]"
+func isDynamicSegmentName(name string) bool {
+	return strings.HasPrefix(name, "[") && strings.HasSuffix(name, "]")
+}
+
+// parseDynamicSegment classifies a bracketed directory or filename stem into
+// its segmentKind and bound parameter name
+func parseDynamicSegment(name string) (segmentKind, string) {
+	switch {
+	case strings.HasPrefix(name, "[[...") && strings.HasSuffix(name, "]]"):
+		return optionalCatchAllSegment, strings.TrimSuffix(strings.TrimPrefix(name, "[[..."), "]]")
+	case strings.HasPrefix(name, "[...") && strings.HasSuffix(name, "]"):
+		return catchAllSegment, strings.TrimSuffix(strings.TrimPrefix(name, "[..."), "]")
+	default:
+		return dynamicSegment, strings.TrimSuffix(strings.TrimPrefix(name, "["), "]")
+	}
+}
+
+// matchRoute walks segments against node, returning the leaf node reached
+// and the dynamic parameters bound along the way. Static children win over
+// the dynamic child at every level, matching Next.js's own precedence.
+func matchRoute(node *routeNode, segments []string, params map[string]string) (*routeNode, bool) {
+	if len(segments) == 0 {
+		if node.dynamicKind == optionalCatchAllSegment && node.dynamic != nil && node.dynamic.pageFile != "" {
+			// An optional catch-all also matches its own empty tail, but only
+			// if the current node has no page of its own to prefer
+			if node.pageFile == "" {
+				return node.dynamic, true
+			}
+		}
+		return node, true
+	}
+
+	seg := segments[0]
+	if child, ok := node.children[seg]; ok {
+		if leaf, ok := matchRoute(child, segments[1:], params); ok {
+			return leaf, true
+		}
+	}
+
+	if node.dynamic == nil {
+		return nil, false
+	}
+
+	switch node.dynamicKind {
+	case catchAllSegment, optionalCatchAllSegment:
+		params[node.dynamicParam] = strings.Join(segments, "/")
+		return node.dynamic, true
+	default: // dynamicSegment
+		params[node.dynamicParam] = seg
+		return matchRoute(node.dynamic, segments[1:], params)
+	}
+}
+
+// collectChain walks root to the matched leaf along the same segments,
+// gathering layout/loading/error/not-found files and parallel slot page
+// files at every level visited
+func collectChain(node *routeNode, segments []string) ([]string, []string, []string, []string, []string) {
+	var layouts, loading, errors, notFound, slots []string
+
+	appendIfSet := func(list *[]string, file string) {
+		if file != "" {
+			*list = append(*list, file)
+		}
+	}
+
+	cur := node
+	appendIfSet(&layouts, cur.layoutFile)
+	appendIfSet(&loading, cur.loadingFile)
+	appendIfSet(&errors, cur.errorFile)
+	appendIfSet(&notFound, cur.notFoundFile)
+	for _, slot := range cur.parallelSlots {
+		appendIfSet(&slots, slot.pageFile)
+	}
+
+	for _, seg := range segments {
+		next, ok := cur.children[seg]
+		if !ok && cur.dynamic != nil {
+			next = cur.dynamic
+			ok = true
+		}
+		if !ok {
+			break
+		}
+		cur = next
+		appendIfSet(&layouts, cur.layoutFile)
+		appendIfSet(&loading, cur.loadingFile)
+		appendIfSet(&errors, cur.errorFile)
+		appendIfSet(&notFound, cur.notFoundFile)
+		for _, slot := range cur.parallelSlots {
+			appendIfSet(&slots, slot.pageFile)
+		}
+	}
+
+	return layouts, loading, errors, notFound, slots
+}
+
+// firstExisting returns dir/<name><ext> if it exists, else "" — the App
+// Router special files are always exactly one name per directory, unlike
+// page files which can also live under src/app
+func firstExisting(dir, name, ext string) string {
+	path := filepath.Join(dir, name+ext)
+	if fileExists(path) {
+		return path
+	}
+	return ""
+}
+
+// splitRoute normalizes a URL path into its non-empty segments
+func splitRoute(route string) []string {
+	route = strings.Trim(filepath.ToSlash(route), "/")
+	if route == "" {
+		return nil
+	}
+	return strings.Split(route, "/")
+}
@@ -0,0 +1,185 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// writeFiles creates each path (relative to root) with empty content,
+// creating parent directories as needed
+func writeFiles(t *testing.T, root string, paths ...string) {
+	t.Helper()
+	for _, p := range paths {
+		full := filepath.Join(root, p)
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatalf("MkdirAll %s: %v", filepath.Dir(full), err)
+		}
+		if err := os.WriteFile(full, nil, 0644); err != nil {
+			t.Fatalf("WriteFile %s: %v", full, err)
+		}
+	}
+}
+
+func TestFindPageFileAppRouter(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root,
+		"app/layout.tsx",
+		"app/page.tsx",
+		"app/about/page.tsx",
+		"app/(marketing)/pricing/page.tsx",
+		"app/(marketing)/layout.tsx",
+		"app/blog/[slug]/page.tsx",
+		"app/blog/[slug]/layout.tsx",
+		"app/docs/[...rest]/page.tsx",
+		"app/shop/[[...rest]]/page.tsx",
+		"app/dashboard/@modal/page.tsx",
+		"app/dashboard/page.tsx",
+	)
+
+	locator := NewNextJSFileLocator(root, "app", true)
+
+	tests := []struct {
+		name       string
+		route      string
+		wantFile   string
+		wantParams map[string]string
+		wantOK     bool
+	}{
+		{"home", "/", "app/page.tsx", map[string]string{}, true},
+		{"static", "/about", "app/about/page.tsx", map[string]string{}, true},
+		{"route group transparent", "/pricing", "app/(marketing)/pricing/page.tsx", map[string]string{}, true},
+		{"dynamic segment", "/blog/hello-world", "app/blog/[slug]/page.tsx", map[string]string{"slug": "hello-world"}, true},
+		{"catch-all", "/docs/a/b/c", "app/docs/[...rest]/page.tsx", map[string]string{"rest": "a/b/c"}, true},
+		{"optional catch-all with segments", "/shop/a/b", "app/shop/[[...rest]]/page.tsx", map[string]string{"rest": "a/b"}, true},
+		{"optional catch-all empty tail", "/shop", "app/shop/[[...rest]]/page.tsx", map[string]string{}, true},
+		{"no match", "/nonexistent/deeply/nested", "", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match, ok := locator.FindPageFile(tt.route)
+			if ok != tt.wantOK {
+				t.Fatalf("FindPageFile(%q) ok = %v, want %v", tt.route, ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+
+			want := filepath.Join(root, tt.wantFile)
+			if match.PageFile != want {
+				t.Errorf("FindPageFile(%q).PageFile = %q, want %q", tt.route, match.PageFile, want)
+			}
+			if !reflect.DeepEqual(match.Params, tt.wantParams) {
+				t.Errorf("FindPageFile(%q).Params = %v, want %v", tt.route, match.Params, tt.wantParams)
+			}
+		})
+	}
+}
+
+func TestFindPageFileAppRouterCollectsLayoutsAndSlots(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root,
+		"app/layout.tsx",
+		"app/blog/[slug]/page.tsx",
+		"app/blog/[slug]/layout.tsx",
+		"app/dashboard/@modal/page.tsx",
+		"app/dashboard/@sidebar/page.tsx",
+		"app/dashboard/page.tsx",
+	)
+
+	locator := NewNextJSFileLocator(root, "app", true)
+
+	match, ok := locator.FindPageFile("/blog/hello-world")
+	if !ok {
+		t.Fatalf("FindPageFile(/blog/hello-world) failed to match")
+	}
+	wantLayouts := []string{
+		filepath.Join(root, "app/layout.tsx"),
+		filepath.Join(root, "app/blog/[slug]/layout.tsx"),
+	}
+	if !reflect.DeepEqual(match.Layouts, wantLayouts) {
+		t.Errorf("Layouts = %v, want %v", match.Layouts, wantLayouts)
+	}
+
+	match, ok = locator.FindPageFile("/dashboard")
+	if !ok {
+		t.Fatalf("FindPageFile(/dashboard) failed to match")
+	}
+	wantSlots := []string{
+		filepath.Join(root, "app/dashboard/@modal/page.tsx"),
+		filepath.Join(root, "app/dashboard/@sidebar/page.tsx"),
+	}
+	gotSlots := append([]string(nil), match.ParallelSlots...)
+	sort.Strings(gotSlots)
+	sort.Strings(wantSlots)
+	if !reflect.DeepEqual(gotSlots, wantSlots) {
+		t.Errorf("ParallelSlots = %v, want %v", gotSlots, wantSlots)
+	}
+}
+
+func TestFindPageFilePagesRouter(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root,
+		"pages/index.tsx",
+		"pages/about.tsx",
+		"pages/blog/[slug].tsx",
+		"pages/blog/[slug]/comments.tsx",
+	)
+
+	locator := NewNextJSFileLocator(root, "pages", true)
+
+	tests := []struct {
+		name       string
+		route      string
+		wantFile   string
+		wantParams map[string]string
+		wantOK     bool
+	}{
+		{"home", "/", "pages/index.tsx", map[string]string{}, true},
+		{"static file", "/about", "pages/about.tsx", map[string]string{}, true},
+		{"dynamic filename", "/blog/hello-world", "pages/blog/[slug].tsx", map[string]string{"slug": "hello-world"}, true},
+		{"dynamic directory", "/blog/hello-world/comments", "pages/blog/[slug]/comments.tsx", map[string]string{"slug": "hello-world"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match, ok := locator.FindPageFile(tt.route)
+			if ok != tt.wantOK {
+				t.Fatalf("FindPageFile(%q) ok = %v, want %v", tt.route, ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+
+			want := filepath.Join(root, tt.wantFile)
+			if match.PageFile != want {
+				t.Errorf("FindPageFile(%q).PageFile = %q, want %q", tt.route, match.PageFile, want)
+			}
+			if !reflect.DeepEqual(match.Params, tt.wantParams) {
+				t.Errorf("FindPageFile(%q).Params = %v, want %v", tt.route, match.Params, tt.wantParams)
+			}
+		})
+	}
+}
+
+func TestFindPageFileStaticWinsOverDynamic(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root,
+		"app/blog/new/page.tsx",
+		"app/blog/[slug]/page.tsx",
+	)
+
+	locator := NewNextJSFileLocator(root, "app", true)
+
+	match, ok := locator.FindPageFile("/blog/new")
+	if !ok {
+		t.Fatalf("FindPageFile(/blog/new) failed to match")
+	}
+	want := filepath.Join(root, "app/blog/new/page.tsx")
+	if match.PageFile != want {
+		t.Errorf("PageFile = %q, want static route %q (static should win over [slug])", match.PageFile, want)
+	}
+}
@@ -10,13 +10,33 @@ import (
 
 // ProjectContext contains information about the project's framework and styling approach
 type ProjectContext struct {
-	Framework    string // "react", "vue", "svelte", "html", "nextjs"
-	Styling      string // "tailwind", "css-modules", "styled-components", "emotion", "css"
+	Framework    string // "react", "vue", "svelte", "sveltekit", "nuxt", "astro", "angular", "html", "nextjs", "remix", "solidstart", "qwik"
+	Styling      string // "tailwind", "css-modules", "styled-components", "emotion", "css", ...
 	TypeScript   bool
 	NextJSRouter string // "app", "pages", "none" - only set if Framework is "nextjs"
+
+	// UILibrary is the detected component library, e.g. "chakra" or
+	// "mantine"; empty if none of UILibraryDetectors matched.
+	UILibrary string
+	// Bundler is set by a framework detector's Apply when that framework
+	// implies a specific bundler (e.g. "vite" for Nuxt, SvelteKit, SolidStart, Qwik)
+	Bundler string
+	// RouterFlavor distinguishes variants of a framework's routing
+	// convention, e.g. Remix's "remix-app" (app/routes) vs "remix-classic"
+	RouterFlavor string
+
+	// RelPath and Role are only set when this ProjectContext came from
+	// DetectWorkspace: RelPath is this package's path relative to the
+	// workspace root, and Role is "app" or "lib" per its heuristic.
+	RelPath string
+	Role    string
 }
 
-// AnalyzeProject detects the project's framework and styling approach
+// AnalyzeProject detects the project's framework and styling approach by
+// running FrameworkDetector, DependencyDetector (styling), and
+// DependencyDetector (UI library) pipelines, in each list's priority order,
+// against the project's package.json dependencies and filesystem. See
+// Detectors, StylingDetectors, and UILibraryDetectors to extend any of them.
 func AnalyzeProject(projectDir string) (*ProjectContext, error) {
 	ctx := &ProjectContext{
 		Framework:  "html",
@@ -51,29 +71,30 @@ func AnalyzeProject(projectDir string) (*ProjectContext, error) {
 		allDeps[k] = true
 	}
 
-	// Detect framework (check Next.js first since it includes React)
-	if allDeps["next"] {
-		ctx.Framework = "nextjs"
-		ctx.NextJSRouter = detectNextJSRouter(projectDir)
-	} else if allDeps["react"] || allDeps["react-dom"] {
-		ctx.Framework = "react"
-	} else if allDeps["vue"] {
-		ctx.Framework = "vue"
-	} else if allDeps["svelte"] {
-		ctx.Framework = "svelte"
-	} else if allDeps["@angular/core"] {
-		ctx.Framework = "angular"
+	s := signals{allDeps: allDeps, projectDir: projectDir}
+
+	for _, d := range sortedFrameworkDetectors() {
+		if d.matches(s) {
+			ctx.Framework = d.Name
+			if d.Apply != nil {
+				d.Apply(ctx, projectDir)
+			}
+			break
+		}
 	}
 
-	// Detect styling approach
-	if allDeps["tailwindcss"] {
-		ctx.Styling = "tailwind"
-	} else if allDeps["styled-components"] {
-		ctx.Styling = "styled-components"
-	} else if allDeps["@emotion/react"] || allDeps["@emotion/styled"] {
-		ctx.Styling = "emotion"
-	} else if hasFileWithSuffix(projectDir, ".module.css") || hasFileWithSuffix(projectDir, ".module.scss") {
-		ctx.Styling = "css-modules"
+	for _, d := range sortedDependencyDetectors(stylingDetectors) {
+		if d.matches(s) {
+			ctx.Styling = d.Name
+			break
+		}
+	}
+
+	for _, d := range sortedDependencyDetectors(uiLibraryDetectors) {
+		if d.matches(s) {
+			ctx.UILibrary = d.Name
+			break
+		}
 	}
 
 	// Detect TypeScript
@@ -158,26 +179,26 @@ func hasFileWithSuffix(projectDir, suffix string) bool {
 	return found
 }
 
-// GetFileExtension returns the appropriate file extension for the project
+// GetFileExtension returns the appropriate file extension for the project,
+// looked up from the FrameworkDetector registered for ctx.Framework rather
+// than a fixed switch, so a framework registered via RegisterDetector gets
+// this for free.
 func (ctx *ProjectContext) GetFileExtension() string {
-	switch ctx.Framework {
-	case "nextjs", "react":
-		if ctx.TypeScript {
-			return ".tsx"
-		}
-		return ".jsx"
-	case "vue":
-		return ".vue"
-	case "svelte":
-		return ".svelte"
-	case "angular":
-		return ".component.ts"
-	default:
+	ext, ok := extensionForFramework(ctx.Framework)
+	if !ok {
 		if ctx.TypeScript {
 			return ".ts"
 		}
 		return ".js"
 	}
+
+	if ext == extJSXFamily {
+		if ctx.TypeScript {
+			return ".tsx"
+		}
+		return ".jsx"
+	}
+	return ext
 }
 
 // String returns a human-readable description of the project context
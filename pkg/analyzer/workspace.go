@@ -0,0 +1,265 @@
+package analyzer
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Workspace describes a monorepo rooted at Root: which package manager (and,
+// if any, build system) declared it, and the AnalyzeProject result for each
+// child package it contains.
+type Workspace struct {
+	Root           string
+	PackageManager string // "pnpm", "yarn", "npm", "bun", or "" if unknown
+	BuildSystem    string // "nx", "turbo", "nx+turbo", or "" if neither is in use
+	Packages       []ProjectContext
+}
+
+// DetectWorkspace reports whether rootDir is a monorepo root — a
+// pnpm-workspace.yaml, a package.json "workspaces" field, or an Nx project
+// with no explicit workspaces field — and if so, runs AnalyzeProject against
+// every child package its glob patterns resolve to. Each resulting
+// ProjectContext has RelPath set to its path relative to rootDir and Role
+// set to "app" or "lib" (a framework detector having matched is treated as
+// "app"; the html default is treated as "lib").
+func DetectWorkspace(rootDir string) (*Workspace, bool, error) {
+	globs, packageManager := workspaceGlobs(rootDir)
+	buildSystem := detectBuildSystem(rootDir)
+
+	if len(globs) == 0 && buildSystem == "nx" {
+		// Nx can infer projects from apps/*, libs/* without any workspaces
+		// field at all; that's its conventional default layout
+		globs = []string{"apps/*", "libs/*"}
+	}
+	if len(globs) == 0 {
+		return nil, false, nil
+	}
+	if packageManager == "" {
+		packageManager = detectPackageManager(rootDir)
+	}
+
+	dirs := expandWorkspaceGlobs(rootDir, globs)
+	packages := make([]ProjectContext, 0, len(dirs))
+	for _, dir := range dirs {
+		ctx, err := AnalyzeProject(dir)
+		if err != nil {
+			continue
+		}
+
+		relPath, err := filepath.Rel(rootDir, dir)
+		if err != nil {
+			relPath = dir
+		}
+		ctx.RelPath = filepath.ToSlash(relPath)
+		ctx.Role = roleForPackage(*ctx)
+		packages = append(packages, *ctx)
+	}
+
+	sort.Slice(packages, func(i, j int) bool { return packages[i].RelPath < packages[j].RelPath })
+
+	return &Workspace{
+		Root:           rootDir,
+		PackageManager: packageManager,
+		BuildSystem:    buildSystem,
+		Packages:       packages,
+	}, true, nil
+}
+
+// PrimaryPackage resolves which package a caller should target when they
+// haven't named one explicitly: the first Next.js app, else the first
+// package with an app Role, else the alphabetically-first package (Packages
+// is already sorted by RelPath).
+func (w *Workspace) PrimaryPackage() (ProjectContext, bool) {
+	if w == nil || len(w.Packages) == 0 {
+		return ProjectContext{}, false
+	}
+
+	for _, pkg := range w.Packages {
+		if pkg.Framework == "nextjs" {
+			return pkg, true
+		}
+	}
+	for _, pkg := range w.Packages {
+		if pkg.Role == "app" {
+			return pkg, true
+		}
+	}
+	return w.Packages[0], true
+}
+
+// workspaceGlobs returns the workspace package-location globs and, when it
+// can be inferred directly from what declared them, the package manager:
+// pnpm-workspace.yaml is pnpm-specific and checked first, then package.json's
+// "workspaces" field (a bare array, or a {packages: [...]} object, per
+// Yarn/npm/Bun's two accepted shapes).
+func workspaceGlobs(rootDir string) ([]string, string) {
+	if data, err := os.ReadFile(filepath.Join(rootDir, "pnpm-workspace.yaml")); err == nil {
+		if globs := parsePnpmWorkspaceGlobs(data); len(globs) > 0 {
+			return globs, "pnpm"
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(rootDir, "package.json"))
+	if err != nil {
+		return nil, ""
+	}
+
+	var pkg struct {
+		Workspaces json.RawMessage `json:"workspaces"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil || len(pkg.Workspaces) == 0 {
+		return nil, ""
+	}
+
+	var list []string
+	if err := json.Unmarshal(pkg.Workspaces, &list); err == nil {
+		return list, ""
+	}
+
+	var obj struct {
+		Packages []string `json:"packages"`
+	}
+	if err := json.Unmarshal(pkg.Workspaces, &obj); err == nil {
+		return obj.Packages, ""
+	}
+
+	return nil, ""
+}
+
+// parsePnpmWorkspaceGlobs extracts a top-level "packages: [...]" list from
+// pnpm-workspace.yaml text. Like devserver's parseRestartOn, this only
+// understands that one narrow shape rather than pulling in a general-purpose
+// YAML dependency for a single list of strings.
+func parsePnpmWorkspaceGlobs(data []byte) []string {
+	var globs []string
+	inPackages := false
+
+	scanner := bufio.NewScanner(strings.NewReader(string(data)))
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+
+		switch {
+		case indent == 0 && trimmed == "packages:":
+			inPackages = true
+		case indent == 0:
+			inPackages = false
+		case inPackages && strings.HasPrefix(trimmed, "- "):
+			item := strings.TrimSpace(strings.TrimPrefix(trimmed, "- "))
+			item = strings.Trim(item, `"'`)
+			if item != "" {
+				globs = append(globs, item)
+			}
+		case inPackages:
+			inPackages = false
+		}
+	}
+
+	return globs
+}
+
+// detectPackageManager infers the package manager in use from whichever
+// lockfile is present at rootDir
+func detectPackageManager(rootDir string) string {
+	switch {
+	case fileExists(filepath.Join(rootDir, "pnpm-lock.yaml")):
+		return "pnpm"
+	case fileExists(filepath.Join(rootDir, "yarn.lock")):
+		return "yarn"
+	case fileExists(filepath.Join(rootDir, "bun.lockb")):
+		return "bun"
+	case fileExists(filepath.Join(rootDir, "package-lock.json")):
+		return "npm"
+	default:
+		return ""
+	}
+}
+
+// detectBuildSystem reports which monorepo build orchestrator, if any, sits
+// on top of the package manager's own workspace support
+func detectBuildSystem(rootDir string) string {
+	nx := fileExists(filepath.Join(rootDir, "nx.json"))
+	turbo := fileExists(filepath.Join(rootDir, "turbo.json"))
+
+	switch {
+	case nx && turbo:
+		return "nx+turbo"
+	case nx:
+		return "nx"
+	case turbo:
+		return "turbo"
+	default:
+		return ""
+	}
+}
+
+// expandWorkspaceGlobs resolves patterns (each optionally prefixed with "!"
+// to exclude rather than include, per Yarn/pnpm convention) against rootDir,
+// returning every resulting directory that has its own package.json, sorted
+// and de-duplicated.
+func expandWorkspaceGlobs(rootDir string, patterns []string) []string {
+	include := make(map[string]bool)
+	exclude := make(map[string]bool)
+
+	for _, pattern := range patterns {
+		negate := strings.HasPrefix(pattern, "!")
+		matches, err := filepath.Glob(filepath.Join(rootDir, strings.TrimPrefix(pattern, "!")))
+		if err != nil {
+			continue
+		}
+
+		for _, match := range matches {
+			info, err := os.Stat(match)
+			if err != nil || !info.IsDir() {
+				continue
+			}
+			if negate {
+				exclude[match] = true
+			} else {
+				include[match] = true
+			}
+		}
+	}
+
+	var dirs []string
+	for dir := range include {
+		if exclude[dir] || isInsideNodeModules(dir) {
+			continue
+		}
+		if !fileExists(filepath.Join(dir, "package.json")) {
+			continue
+		}
+		dirs = append(dirs, dir)
+	}
+
+	sort.Strings(dirs)
+	return dirs
+}
+
+// isInsideNodeModules reports whether any path segment of dir is "node_modules"
+func isInsideNodeModules(dir string) bool {
+	for _, seg := range strings.Split(filepath.ToSlash(dir), "/") {
+		if seg == "node_modules" {
+			return true
+		}
+	}
+	return false
+}
+
+// roleForPackage applies the "app vs lib" heuristic: a package a framework
+// detector matched is treated as an app; the unmatched "html" default is
+// treated as a shared library
+func roleForPackage(ctx ProjectContext) string {
+	if ctx.Framework == "html" {
+		return "lib"
+	}
+	return "app"
+}
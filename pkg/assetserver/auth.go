@@ -0,0 +1,94 @@
+package assetserver
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/websocket"
+)
+
+// requireOrigin enforces the server's allowed-origin list against r's Origin
+// header. Requests with no Origin header (same-origin navigations, curl,
+// the proxy's own HTML injection) are always allowed, since browsers only
+// send Origin on cross-origin and some same-origin requests. It writes a 403
+// and returns false on a mismatch, logging the offending origin when verbose.
+func (s *Server) requireOrigin(w http.ResponseWriter, r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" || s.isOriginAllowed(origin) {
+		return true
+	}
+
+	if s.verbose {
+		fmt.Printf("[Asset Server] 🚫 Rejected request from disallowed origin: %s\n", origin)
+	}
+	http.Error(w, "origin not allowed", http.StatusForbidden)
+	return false
+}
+
+func (s *Server) isOriginAllowed(origin string) bool {
+	for _, allowed := range s.allowedOrigins {
+		if origin == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// wsUpgrader returns a websocket.Upgrader whose CheckOrigin enforces this
+// server's origin allowlist directly, the same way requireOrigin does for
+// plain HTTP requests. Handlers should still call requireOrigin/requireWSToken
+// before upgrading so a rejection gets a proper 403 instead of a bare
+// connection refusal, but the allowlist itself is now enforced here too, so a
+// handler that forgets that call can't silently accept a disallowed origin.
+func (s *Server) wsUpgrader() websocket.Upgrader {
+	return websocket.Upgrader{
+		CheckOrigin: func(r *http.Request) bool {
+			origin := r.Header.Get("Origin")
+			return origin == "" || s.isOriginAllowed(origin)
+		},
+	}
+}
+
+// requireToken enforces the server's shared-secret session token on REST
+// requests via the X-Layrr-Token header, minted by config.NewSessionToken
+// and baked into the injected client script at serve time
+func (s *Server) requireToken(w http.ResponseWriter, r *http.Request) bool {
+	if r.Header.Get("X-Layrr-Token") == s.authToken {
+		return true
+	}
+
+	if s.verbose {
+		fmt.Printf("[Asset Server] 🚫 Rejected request with missing/invalid session token from origin: %s\n", r.Header.Get("Origin"))
+	}
+	http.Error(w, "invalid session token", http.StatusForbidden)
+	return false
+}
+
+// requireWSToken enforces the server's shared-secret session token on
+// WebSocket upgrade requests via the Sec-WebSocket-Protocol header, since
+// WebSocket clients can't set arbitrary request headers
+func (s *Server) requireWSToken(w http.ResponseWriter, r *http.Request) bool {
+	for _, protocol := range websocket.Subprotocols(r) {
+		if protocol == s.authToken {
+			return true
+		}
+	}
+
+	if s.verbose {
+		fmt.Printf("[Asset Server] 🚫 Rejected WebSocket upgrade with missing/invalid session token from origin: %s\n", r.Header.Get("Origin"))
+	}
+	http.Error(w, "invalid session token", http.StatusForbidden)
+	return false
+}
+
+// requireLiveEventsToken enforces the session token on /__layrr/ws/events,
+// which (unlike the other WebSocket endpoints) falls back to SSE for
+// clients that don't negotiate the upgrade; EventSource can't set the
+// Sec-WebSocket-Protocol header or any other custom header, so a `token`
+// query parameter is accepted there too
+func (s *Server) requireLiveEventsToken(w http.ResponseWriter, r *http.Request) bool {
+	if r.URL.Query().Get("token") == s.authToken {
+		return true
+	}
+	return s.requireWSToken(w, r)
+}
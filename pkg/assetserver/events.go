@@ -0,0 +1,165 @@
+package assetserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/thetronjohnson/layrr/pkg/devserver"
+)
+
+// liveEvent is the stable envelope pushed to subscribers of /__layrr/ws/events:
+// dev server lifecycle transitions, file watcher reloads, and parsed build
+// errors all take this same shape so third-party tooling can subscribe too.
+type liveEvent struct {
+	Type    string      `json:"type"`
+	Ts      int64       `json:"ts"`
+	Payload interface{} `json:"payload"`
+}
+
+// buildErrorPattern matches the file:line(:col) a Vite/Next/webpack dev
+// server prints at the start of an error frame, e.g.
+// "Error: ... at /src/App.tsx:12:3" or "./src/App.tsx:14:8 Module not found: ..."
+var buildErrorPattern = regexp.MustCompile(`(?i)([.\w@/-][\w./@-]*\.(?:tsx?|jsx?|vue|css|scss)):(\d+)(?::(\d+))?`)
+
+// emitEvent wraps payload in the stable envelope and fans it out to every
+// connected WebSocket and SSE subscriber of /__layrr/ws/events
+func (s *Server) emitEvent(eventType string, payload interface{}) {
+	evt := liveEvent{Type: eventType, Ts: time.Now().UnixMilli(), Payload: payload}
+
+	s.eventClientsMu.Lock()
+	for client := range s.eventClients {
+		if err := client.WriteJSON(evt); err != nil {
+			delete(s.eventClients, client)
+			client.Close()
+		}
+	}
+	s.eventClientsMu.Unlock()
+
+	s.sseClientsMu.Lock()
+	for ch := range s.sseClients {
+		select {
+		case ch <- evt:
+		default: // slow subscriber, drop rather than block the broadcaster
+		}
+	}
+	s.sseClientsMu.Unlock()
+}
+
+// watchDevServerEvents relays manager's lifecycle transitions onto the live
+// event feed until a newer manager replaces it
+func (s *Server) watchDevServerEvents(manager *devserver.Manager) {
+	ch := manager.Subscribe()
+	defer manager.Unsubscribe(ch)
+
+	for evt := range ch {
+		if s.devServer != manager {
+			return
+		}
+		s.emitEvent("devserver:state", evt)
+	}
+}
+
+// watchDevServerLogs scans manager's captured stdout/stderr for a build-error
+// frame (Vite/Next/webpack all print a "file:line:col" near the top of one)
+// and emits a build:error event with the offending location
+func (s *Server) watchDevServerLogs(manager *devserver.Manager) {
+	lines := manager.SubscribeLogs()
+	defer manager.UnsubscribeLogs(lines)
+
+	for line := range lines {
+		if s.devServer != manager {
+			return
+		}
+
+		match := buildErrorPattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+
+		s.emitEvent("build:error", map[string]string{
+			"file":    match[1],
+			"line":    match[2],
+			"column":  match[3],
+			"message": line,
+		})
+	}
+}
+
+// handleEventsWebSocket upgrades to a WebSocket and streams the live event
+// feed. Falls back to SSE for clients that don't negotiate the upgrade.
+func (s *Server) handleEventsWebSocket(w http.ResponseWriter, r *http.Request) {
+	if !s.requireOrigin(w, r) || !s.requireLiveEventsToken(w, r) {
+		return
+	}
+
+	if !websocket.IsWebSocketUpgrade(r) {
+		s.handleEventsSSE(w, r)
+		return
+	}
+
+	conn, err := s.wsUpgrader().Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	s.eventClientsMu.Lock()
+	s.eventClients[conn] = true
+	s.eventClientsMu.Unlock()
+
+	defer func() {
+		s.eventClientsMu.Lock()
+		delete(s.eventClients, conn)
+		s.eventClientsMu.Unlock()
+	}()
+
+	for {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			return
+		}
+	}
+}
+
+// handleEventsSSE streams the same live event feed as Server-Sent Events
+func (s *Server) handleEventsSSE(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	ch := make(chan liveEvent, 16)
+	s.sseClientsMu.Lock()
+	s.sseClients[ch] = true
+	s.sseClientsMu.Unlock()
+
+	defer func() {
+		s.sseClientsMu.Lock()
+		delete(s.sseClients, ch)
+		s.sseClientsMu.Unlock()
+	}()
+
+	for {
+		select {
+		case evt := <-ch:
+			data, err := json.Marshal(evt)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "data: %s\n\n", data)
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package assetserver
+
+import (
+	"sync"
+
+	"github.com/gorilla/websocket"
+	"github.com/thetronjohnson/layrr/pkg/claude"
+)
+
+// wsProgressWriter implements claude.ProgressWriter by serializing each call
+// into a JSON frame over a message WebSocket connection, guarded by a
+// per-connection mutex since gorilla/websocket connections aren't safe for
+// concurrent writes. The final complete/error frame for id is still sent by
+// the caller after HandleMessage returns, so today's inject script (which only
+// looks for "complete"/"error") keeps working unchanged.
+type wsProgressWriter struct {
+	conn *websocket.Conn
+	id   int
+	mu   *sync.Mutex
+}
+
+// newWSProgressWriter returns a ProgressWriter that streams progress frames
+// for message id over conn, serialized by mu
+func newWSProgressWriter(conn *websocket.Conn, id int, mu *sync.Mutex) *wsProgressWriter {
+	return &wsProgressWriter{conn: conn, id: id, mu: mu}
+}
+
+func (w *wsProgressWriter) write(frame map[string]interface{}) {
+	frame["id"] = w.id
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.conn.WriteJSON(frame)
+}
+
+// Status streams a "status" frame with a free-form message
+func (w *wsProgressWriter) Status(msg string) {
+	w.write(map[string]interface{}{"status": "status", "message": msg})
+}
+
+// Progress streams a "progress" frame with step/total/message
+func (w *wsProgressWriter) Progress(step, total int, msg string) {
+	w.write(map[string]interface{}{"status": "progress", "step": step, "total": total, "message": msg})
+}
+
+// Error streams a "progress-error" frame. The caller still sends the final
+// "error" status after HandleMessage returns; this is the earlier, incremental signal.
+func (w *wsProgressWriter) Error(err error) {
+	w.write(map[string]interface{}{"status": "progress-error", "message": err.Error()})
+}
+
+var _ claude.ProgressWriter = (*wsProgressWriter)(nil)
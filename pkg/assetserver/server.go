@@ -7,12 +7,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/http/httputil"
 	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -20,34 +22,117 @@ import (
 	"github.com/thetronjohnson/layrr/pkg/analyzer"
 	"github.com/thetronjohnson/layrr/pkg/bridge"
 	"github.com/thetronjohnson/layrr/pkg/config"
+	"github.com/thetronjohnson/layrr/pkg/devserver"
 	"github.com/thetronjohnson/layrr/pkg/proxy"
+	"github.com/thetronjohnson/layrr/pkg/rewriter"
 	"github.com/thetronjohnson/layrr/pkg/watcher"
 )
 
 //go:embed assets/*
 var assets embed.FS
 
-var upgrader = websocket.Upgrader{
-	CheckOrigin: func(r *http.Request) bool {
-		return true // Allow all origins for local development
-	},
-}
-
 // Server serves Layrr assets, handles WebSocket communication, and proxies to dev server with script injection
 type Server struct {
-	port          int
-	targetPort    int
-	projectDir    string
-	bridge        *bridge.Bridge
-	watcher       *watcher.Watcher
-	httpServer    *http.Server
-	proxy         *httputil.ReverseProxy
-	verbose       bool
-	reloadClients map[*websocket.Conn]bool
+	port           int
+	targetPort     int
+	projectDir     string
+	bridgeMu       sync.RWMutex
+	bridge         *bridge.Bridge
+	watcher        *watcher.Watcher
+	httpServer     *http.Server
+	proxy          *httputil.ReverseProxy
+	verbose        bool
+	reloadClients  map[*websocket.Conn]bool
+	devServer      *devserver.Manager
+	networkMu      sync.RWMutex
+	networkProfile proxy.NetworkProfile
+	settingsMu     sync.RWMutex
+	proxySettings  proxy.Settings
+	allowedOrigins []string
+	authToken      string
+
+	eventClients   map[*websocket.Conn]bool
+	eventClientsMu sync.Mutex
+	sseClients     map[chan liveEvent]bool
+	sseClientsMu   sync.Mutex
 }
 
-// NewServer creates a new asset server
-func NewServer(port int, targetPort int, projectDir string, bridge *bridge.Bridge, watcher *watcher.Watcher, verbose bool) *Server {
+// SetDevServerManager attaches the dev server supervisor so its captured logs
+// can be streamed over /__layrr/ws/devserver-logs, and its lifecycle
+// transitions and parsed build errors over /__layrr/ws/events
+func (s *Server) SetDevServerManager(m *devserver.Manager) {
+	s.devServer = m
+	go s.watchDevServerEvents(m)
+	go s.watchDevServerLogs(m)
+}
+
+// SetBridge swaps the dispatcher used for incoming chat messages, e.g. after
+// the user switches AI backends via App.SetBackend. Takes effect for new
+// messages; a message already being handled keeps whatever bridge was active
+// when it arrived.
+func (s *Server) SetBridge(b *bridge.Bridge) {
+	s.bridgeMu.Lock()
+	defer s.bridgeMu.Unlock()
+	s.bridge = b
+}
+
+func (s *Server) currentBridge() *bridge.Bridge {
+	s.bridgeMu.RLock()
+	defer s.bridgeMu.RUnlock()
+	return s.bridge
+}
+
+// SetNetworkProfile changes the simulated network condition applied to the
+// proxied connection. Takes effect for new connections; existing connections
+// keep whatever profile was active when they were accepted.
+func (s *Server) SetNetworkProfile(profile proxy.NetworkProfile) {
+	s.networkMu.Lock()
+	defer s.networkMu.Unlock()
+	s.networkProfile = profile
+}
+
+func (s *Server) currentNetworkProfile() proxy.NetworkProfile {
+	s.networkMu.RLock()
+	defer s.networkMu.RUnlock()
+	return s.networkProfile
+}
+
+// SetProxySettings changes the per-project request/response rewriting and
+// host allowlist applied to the proxied connection. Takes effect for new
+// requests; requests already in flight keep whatever settings were active
+// when they arrived.
+func (s *Server) SetProxySettings(settings proxy.Settings) {
+	s.settingsMu.Lock()
+	defer s.settingsMu.Unlock()
+	s.proxySettings = settings
+}
+
+func (s *Server) currentProxySettings() proxy.Settings {
+	s.settingsMu.RLock()
+	defer s.settingsMu.RUnlock()
+	return s.proxySettings
+}
+
+// defaultAllowedOrigins returns the origins a freshly proxied dev server is
+// expected to be browsed from, used when NewServer isn't given its own list
+func defaultAllowedOrigins(targetPort int) []string {
+	return []string{
+		fmt.Sprintf("http://localhost:%d", targetPort),
+		fmt.Sprintf("http://127.0.0.1:%d", targetPort),
+	}
+}
+
+// NewServer creates a new asset server. allowedOrigins restricts which
+// Origin a browser request may come from before it reaches any /__layrr/*
+// endpoint; a nil/empty list falls back to localhost/127.0.0.1 on
+// targetPort. token is the shared-secret session token (see
+// config.NewSessionToken) required on those endpoints so another page open
+// in the same browser can't drive this project's proxy.
+func NewServer(port int, targetPort int, projectDir string, bridge *bridge.Bridge, watcher *watcher.Watcher, verbose bool, allowedOrigins []string, token string) *Server {
+	if len(allowedOrigins) == 0 {
+		allowedOrigins = defaultAllowedOrigins(targetPort)
+	}
+
 	// Create reverse proxy to dev server
 	target := &url.URL{
 		Scheme: "http",
@@ -59,26 +144,32 @@ func NewServer(port int, targetPort int, projectDir string, bridge *bridge.Bridg
 	// Save original director
 	originalDirector := proxy.Director
 
-	// Customize proxy behavior
+	server := &Server{
+		port:           port,
+		targetPort:     targetPort,
+		projectDir:     projectDir,
+		bridge:         bridge,
+		watcher:        watcher,
+		proxy:          proxy,
+		verbose:        verbose,
+		allowedOrigins: allowedOrigins,
+		authToken:      token,
+		reloadClients:  make(map[*websocket.Conn]bool),
+		eventClients:   make(map[*websocket.Conn]bool),
+		sseClients:     make(map[chan liveEvent]bool),
+	}
+
 	proxy.Director = func(req *http.Request) {
 		originalDirector(req)
 		// Remove Accept-Encoding to prevent compressed responses (so we can inject script)
 		req.Header.Del("Accept-Encoding")
-	}
-
-	server := &Server{
-		port:          port,
-		targetPort:    targetPort,
-		projectDir:    projectDir,
-		bridge:        bridge,
-		watcher:       watcher,
-		proxy:         proxy,
-		verbose:       verbose,
-		reloadClients: make(map[*websocket.Conn]bool),
+		server.currentProxySettings().ApplyToRequest(req)
 	}
 
 	// Set up ModifyResponse once during initialization (thread-safe)
 	proxy.ModifyResponse = func(resp *http.Response) error {
+		server.currentProxySettings().ApplyToResponse(resp)
+
 		// Remove X-Frame-Options to allow iframe embedding in Wails app
 		resp.Header.Del("X-Frame-Options")
 		// Also remove Content-Security-Policy frame-ancestors if present
@@ -119,22 +210,25 @@ func NewServer(port int, targetPort int, projectDir string, bridge *bridge.Bridg
 
 	// Subscribe to file watcher events
 	if watcher != nil {
-		watcher.OnChange(func() {
-			server.notifyReload()
+		watcher.OnChange(func(files []string) {
+			server.notifyReload(files)
+		})
+		watcher.OnChange(func(files []string) {
+			server.emitEvent("watcher:reload", map[string]interface{}{"files": files})
 		})
 	}
 
 	return server
 }
 
-// notifyReload sends reload notification to all connected clients
-func (s *Server) notifyReload() {
+// notifyReload sends a reload notification, naming the changed files, to all connected clients
+func (s *Server) notifyReload(files []string) {
 	if s.verbose {
-		fmt.Println("[Asset Server] 🔄 Notifying clients of file changes")
+		fmt.Printf("[Asset Server] 🔄 Notifying clients of file changes: %v\n", files)
 	}
 
 	for client := range s.reloadClients {
-		err := client.WriteJSON(map[string]string{"type": "reload"})
+		err := client.WriteJSON(map[string]interface{}{"type": "reload", "files": files})
 		if err != nil {
 			// Client disconnected, remove it
 			delete(s.reloadClients, client)
@@ -159,6 +253,13 @@ func (s *Server) Start() error {
 	// WebSocket endpoint for reload notifications
 	mux.HandleFunc("/__layrr/ws/reload", s.handleReloadWebSocket)
 
+	// WebSocket endpoint streaming dev server stdout/stderr
+	mux.HandleFunc("/__layrr/ws/devserver-logs", s.handleDevServerLogsWebSocket)
+
+	// WebSocket (falls back to SSE) endpoint multiplexing dev server lifecycle
+	// transitions, file watcher reloads, and parsed build errors
+	mux.HandleFunc("/__layrr/ws/events", s.handleEventsWebSocket)
+
 	// HTTP endpoint for immediate image upload
 	mux.HandleFunc("/__layrr/upload-image", s.handleImageUpload)
 
@@ -173,18 +274,64 @@ func (s *Server) Start() error {
 		Handler: mux,
 	}
 
+	listener, err := net.Listen("tcp", s.httpServer.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.httpServer.Addr, err)
+	}
+	listener = proxy.NewSlowListener(listener, s.currentNetworkProfile())
+
 	fmt.Printf("🚀 Layrr proxy server starting on http://localhost:%d\n", s.port)
 	fmt.Printf("   Proxying to: http://localhost:%d\n", s.targetPort)
-	return s.httpServer.ListenAndServe()
+	return s.httpServer.Serve(listener)
 }
 
-// handleProxyWithInjection proxies requests to dev server (ModifyResponse is already set)
+// handleProxyWithInjection proxies requests to dev server (ModifyResponse is already set),
+// first enforcing the configured host allowlist and applying any simulated
+// latency or packet loss from the active network profile
 func (s *Server) handleProxyWithInjection(w http.ResponseWriter, r *http.Request) {
-	// The ModifyResponse function is already configured in NewServer
-	// Just serve the proxied request
+	if !s.currentProxySettings().IsHostAllowed(r.Host) {
+		http.Error(w, "host not allowed", http.StatusForbidden)
+		return
+	}
+
+	profile := s.currentNetworkProfile()
+
+	drop, latency := proxy.ShouldDrop(profile)
+	if latency > 0 {
+		time.Sleep(latency)
+	}
+	if drop {
+		http.Error(w, "simulated network failure", http.StatusInternalServerError)
+		return
+	}
+
+	s.holdDuringRestart()
 	s.proxy.ServeHTTP(w, r)
 }
 
+// restartHoldTimeout bounds how long a request waits out a config-triggered
+// dev server restart before falling through to whatever the proxy returns
+const restartHoldTimeout = 3 * time.Second
+
+// holdDuringRestart blocks briefly while the dev server is mid-restart
+// (devserver.Watcher's config-change restart puts it through Backoff then
+// Starting) instead of forwarding straight into a 502
+func (s *Server) holdDuringRestart() {
+	if s.devServer == nil {
+		return
+	}
+
+	deadline := time.Now().Add(restartHoldTimeout)
+	for time.Now().Before(deadline) {
+		switch s.devServer.State() {
+		case devserver.StateBackoff, devserver.StateStarting:
+			time.Sleep(100 * time.Millisecond)
+		default:
+			return
+		}
+	}
+}
+
 // Shutdown gracefully shuts down the server
 func (s *Server) Shutdown(ctx context.Context) error {
 	if s.httpServer != nil {
@@ -193,7 +340,16 @@ func (s *Server) Shutdown(ctx context.Context) error {
 	return nil
 }
 
-// handleInjectScript serves the inject script
+// injectScriptTokenPlaceholder marks where handleInjectScript substitutes
+// the session token into the embedded script. This endpoint is necessarily
+// served without the X-Layrr-Token check the other /__layrr/* endpoints
+// require (it's how the legitimate page learns the token in the first
+// place), so it's the one place that token must never be logged or echoed
+// back in a way a third party could fish it out of.
+const injectScriptTokenPlaceholder = "__LAYRR_SESSION_TOKEN__"
+
+// handleInjectScript serves the inject script with this run's session token
+// baked in, so the client can attach it to its /__layrr/* requests
 func (s *Server) handleInjectScript(w http.ResponseWriter, r *http.Request) {
 	// Read the inject script from embedded assets
 	content, err := assets.ReadFile("assets/inject-minimal.js")
@@ -202,9 +358,11 @@ func (s *Server) handleInjectScript(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	script := strings.Replace(string(content), injectScriptTokenPlaceholder, s.authToken, 1)
+
 	w.Header().Set("Content-Type", "application/javascript")
 	w.Header().Set("Cache-Control", "no-cache") // No cache during development
-	w.Write(content)
+	w.Write([]byte(script))
 }
 
 // handleCursorAsset serves the custom cursor SVG
@@ -222,7 +380,11 @@ func (s *Server) handleCursorAsset(w http.ResponseWriter, r *http.Request) {
 
 // handleMessageWebSocket handles WebSocket connections for messaging
 func (s *Server) handleMessageWebSocket(w http.ResponseWriter, r *http.Request) {
-	conn, err := upgrader.Upgrade(w, r, nil)
+	if !s.requireOrigin(w, r) || !s.requireWSToken(w, r) {
+		return
+	}
+
+	conn, err := s.wsUpgrader().Upgrade(w, r, nil)
 	if err != nil {
 		if s.verbose {
 			fmt.Printf("[Asset Server] Failed to upgrade WebSocket: %v\n", err)
@@ -235,6 +397,8 @@ func (s *Server) handleMessageWebSocket(w http.ResponseWriter, r *http.Request)
 		fmt.Println("[Asset Server] Message WebSocket connected")
 	}
 
+	var writeMu sync.Mutex
+
 	// Read messages from the browser
 	for {
 		_, message, err := conn.ReadMessage()
@@ -252,7 +416,7 @@ func (s *Server) handleMessageWebSocket(w http.ResponseWriter, r *http.Request)
 		if err := json.Unmarshal(message, &msgType); err == nil {
 			if msgType.Type == "analyze-design" {
 				// Handle design analysis
-				s.handleDesignAnalysis(conn, message)
+				s.handleDesignAnalysis(conn, message, &writeMu)
 				continue
 			} else if msgType.Type == "direct-image-replace" {
 				// Handle direct image replacement
@@ -273,16 +437,21 @@ func (s *Server) handleMessageWebSocket(w http.ResponseWriter, r *http.Request)
 
 		// Send acknowledgment that message was received
 		fmt.Printf("[Asset Server] 📨 Sending 'received' ack for message ID %d\n", msg.ID)
+		writeMu.Lock()
 		conn.WriteJSON(map[string]interface{}{
 			"id":     msg.ID,
 			"status": "received",
 		})
+		writeMu.Unlock()
 
-		// Handle the message (this blocks until Claude Code finishes)
+		// Handle the message, streaming progress frames as Claude Code runs
+		// instead of only learning the outcome once it finishes
 		fmt.Printf("[Asset Server] ⏳ Processing message ID %d...\n", msg.ID)
-		err = s.bridge.HandleMessage(msg)
+		pw := newWSProgressWriter(conn, msg.ID, &writeMu)
+		err = s.currentBridge().HandleMessage(msg, pw)
 
 		// Send completion status with write deadline (2 minutes to handle slow connections)
+		writeMu.Lock()
 		if err != nil {
 			fmt.Printf("[Asset Server] ❌ Sending 'error' status for message ID %d: %v\n", msg.ID, err)
 			conn.SetWriteDeadline(time.Now().Add(2 * time.Minute))
@@ -305,12 +474,17 @@ func (s *Server) handleMessageWebSocket(w http.ResponseWriter, r *http.Request)
 				fmt.Printf("[Asset Server] ✅ Successfully sent 'complete' for message ID %d\n", msg.ID)
 			}
 		}
+		writeMu.Unlock()
 	}
 }
 
 // handleReloadWebSocket handles WebSocket connections for reload notifications
 func (s *Server) handleReloadWebSocket(w http.ResponseWriter, r *http.Request) {
-	conn, err := upgrader.Upgrade(w, r, nil)
+	if !s.requireOrigin(w, r) || !s.requireWSToken(w, r) {
+		return
+	}
+
+	conn, err := s.wsUpgrader().Upgrade(w, r, nil)
 	if err != nil {
 		if s.verbose {
 			fmt.Printf("[Asset Server] Failed to upgrade reload WebSocket: %v\n", err)
@@ -341,8 +515,45 @@ func (s *Server) handleReloadWebSocket(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// handleDevServerLogsWebSocket streams captured dev server stdout/stderr to the
+// client: the ring-buffered backlog first, then live lines as they're captured
+func (s *Server) handleDevServerLogsWebSocket(w http.ResponseWriter, r *http.Request) {
+	if s.devServer == nil {
+		http.Error(w, "dev server is not running", http.StatusServiceUnavailable)
+		return
+	}
+
+	if !s.requireOrigin(w, r) || !s.requireWSToken(w, r) {
+		return
+	}
+
+	conn, err := s.wsUpgrader().Upgrade(w, r, nil)
+	if err != nil {
+		if s.verbose {
+			fmt.Printf("[Asset Server] Failed to upgrade devserver-logs WebSocket: %v\n", err)
+		}
+		return
+	}
+	defer conn.Close()
+
+	for _, line := range s.devServer.GetLogs(0) {
+		if conn.WriteMessage(websocket.TextMessage, []byte(line)) != nil {
+			return
+		}
+	}
+
+	lines := s.devServer.SubscribeLogs()
+	defer s.devServer.UnsubscribeLogs(lines)
+
+	for line := range lines {
+		if conn.WriteMessage(websocket.TextMessage, []byte(line)) != nil {
+			return
+		}
+	}
+}
+
 // handleDesignAnalysis handles design-to-code image analysis requests
-func (s *Server) handleDesignAnalysis(conn *websocket.Conn, message []byte) {
+func (s *Server) handleDesignAnalysis(conn *websocket.Conn, message []byte, writeMu *sync.Mutex) {
 	// Parse the design analysis request
 	var req struct {
 		Type      string `json:"type"`
@@ -436,9 +647,10 @@ Create a complete, production-ready component that matches this design EXACTLY.
 		Screenshot:  "", // Already analyzed, don't send again
 	}
 
-	// Handle the message through the bridge (this blocks until Claude Code finishes)
+	// Handle the message through the bridge, streaming progress frames as
+	// Claude Code implements the design
 	fmt.Printf("[Asset Server] ⏳ Processing design implementation...\n")
-	err = s.bridge.HandleMessage(bridgeMsg)
+	err = s.currentBridge().HandleMessage(bridgeMsg, newWSProgressWriter(conn, req.ID, writeMu))
 
 	// Send completion status
 	conn.SetWriteDeadline(time.Now().Add(2 * time.Minute))
@@ -462,10 +674,12 @@ Create a complete, production-ready component that matches this design EXACTLY.
 func (s *Server) handleListImages(w http.ResponseWriter, r *http.Request) {
 	fmt.Printf("[AssetServer] 🔵 handleListImages called - Method: %s, Path: %s\n", r.Method, r.URL.Path)
 
-	// Set CORS headers
-	w.Header().Set("Access-Control-Allow-Origin", "*")
+	// Set CORS headers, echoing the request's origin only if it's allowed
+	if origin := r.Header.Get("Origin"); s.isOriginAllowed(origin) {
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+	}
 	w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
-	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, X-Layrr-Token")
 
 	// Handle preflight OPTIONS request
 	if r.Method == http.MethodOptions {
@@ -474,6 +688,10 @@ func (s *Server) handleListImages(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !s.requireOrigin(w, r) || !s.requireToken(w, r) {
+		return
+	}
+
 	// Only accept GET requests
 	if r.Method != http.MethodGet {
 		fmt.Printf("[AssetServer] ❌ Method not allowed: %s\n", r.Method)
@@ -481,12 +699,19 @@ func (s *Server) handleListImages(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	ctx, err := analyzer.AnalyzeProject(s.projectDir)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to analyze project: %v", err), http.StatusInternalServerError)
+		return
+	}
+	publicDir := ctx.Adapter().PublicDir(s.projectDir)
+
 	fmt.Printf("[AssetServer] 📋 Listing images in public directory\n")
 	fmt.Printf("[AssetServer] Project directory: %s\n", s.projectDir)
-	fmt.Printf("[AssetServer] Looking for images in: %s/public\n", s.projectDir)
+	fmt.Printf("[AssetServer] Looking for images in: %s\n", publicDir)
 
 	// List all images
-	images, err := proxy.ListImagesInPublic(s.projectDir)
+	images, err := proxy.ListImagesInPublic(publicDir)
 	if err != nil {
 		fmt.Printf("[AssetServer] ❌ Error listing images: %v\n", err)
 		http.Error(w, fmt.Sprintf("Failed to list images: %v", err), http.StatusInternalServerError)
@@ -508,10 +733,12 @@ func (s *Server) handleListImages(w http.ResponseWriter, r *http.Request) {
 
 // handleImageUpload handles immediate image upload when user selects a file
 func (s *Server) handleImageUpload(w http.ResponseWriter, r *http.Request) {
-	// Set CORS headers
-	w.Header().Set("Access-Control-Allow-Origin", "*")
+	// Set CORS headers, echoing the request's origin only if it's allowed
+	if origin := r.Header.Get("Origin"); s.isOriginAllowed(origin) {
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+	}
 	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
-	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, X-Layrr-Token")
 
 	// Handle preflight OPTIONS request
 	if r.Method == http.MethodOptions {
@@ -519,6 +746,10 @@ func (s *Server) handleImageUpload(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !s.requireOrigin(w, r) || !s.requireToken(w, r) {
+		return
+	}
+
 	// Only accept POST requests
 	if r.Method != http.MethodPost {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -529,6 +760,8 @@ func (s *Server) handleImageUpload(w http.ResponseWriter, r *http.Request) {
 	var requestData struct {
 		Image     string `json:"image"`
 		ImageType string `json:"imageType"`
+		Alt       string `json:"alt"`
+		Source    string `json:"source"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&requestData); err != nil {
@@ -545,32 +778,28 @@ func (s *Server) handleImageUpload(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Check if project is Next.js (for now, only Next.js is supported)
 	ctx, err := analyzer.AnalyzeProject(s.projectDir)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Failed to analyze project: %v", err), http.StatusInternalServerError)
 		return
 	}
 
-	if ctx.Framework != "nextjs" {
-		http.Error(w, "Image attachment is currently only supported for Next.js projects", http.StatusBadRequest)
-		return
-	}
-
-	// Save the image immediately
-	imagePath, err := proxy.SaveImageToProject(requestData.Image, requestData.ImageType, s.projectDir)
+	// Save the image immediately into this framework's public asset directory
+	publicDir := ctx.Adapter().PublicDir(s.projectDir)
+	processed, err := proxy.SaveImageToProject(requestData.Image, requestData.ImageType, s.projectDir, publicDir, proxy.SaveImageOptions{
+		Alt:    requestData.Alt,
+		Source: requestData.Source,
+	})
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Failed to save image: %v", err), http.StatusInternalServerError)
 		return
 	}
 
-	fmt.Printf("[AssetServer] ✅ Image saved successfully: %s\n", imagePath)
+	fmt.Printf("[AssetServer] ✅ Image saved successfully: %s\n", processed.OriginalPath)
 
-	// Return the path as JSON
+	// Return the processed image (original path plus any responsive variants) as JSON
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]string{
-		"path": imagePath,
-	})
+	json.NewEncoder(w).Encode(processed)
 }
 
 // handleDirectImageReplace handles direct image path replacement without Claude Code
@@ -612,17 +841,27 @@ func (s *Server) handleDirectImageReplace(conn *websocket.Conn, message []byte)
 	oldPath := req.Payload.OldPath
 	newPath := req.Payload.NewPath
 
-	// Extract original path from Next.js optimized URL if needed
-	originalPath := extractOriginalImagePath(oldPath)
+	// Extract original path from the framework's image-optimizer URL if needed
+	ctx, err := analyzer.AnalyzeProject(s.projectDir)
+	if err != nil {
+		fmt.Printf("[AssetServer] ❌ Failed to analyze project: %v\n", err)
+		conn.WriteJSON(map[string]interface{}{
+			"status": "error",
+			"error":  fmt.Sprintf("Failed to analyze project: %v", err),
+		})
+		return
+	}
+
+	originalPath := ctx.Adapter().ExtractOriginalAssetURL(oldPath)
 	if originalPath != oldPath {
-		fmt.Printf("[AssetServer] 📦 Detected Next.js URL, extracted original: %s\n", originalPath)
+		fmt.Printf("[AssetServer] 📦 Detected image-optimizer URL, extracted original: %s\n", originalPath)
 		oldPath = originalPath
 	}
 
 	fmt.Printf("[AssetServer] 🔍 Searching for files containing: %s\n", oldPath)
 
 	// Search all source files in the project
-	replaced, err := s.replaceImagePathInFiles(oldPath, newPath)
+	edits, err := s.replaceImagePathInFiles(ctx.Adapter(), oldPath, newPath)
 
 	// Send response
 	conn.SetWriteDeadline(time.Now().Add(2 * time.Minute))
@@ -632,24 +871,44 @@ func (s *Server) handleDirectImageReplace(conn *websocket.Conn, message []byte)
 			"status": "error",
 			"error":  err.Error(),
 		})
-	} else if !replaced {
+	} else if len(edits) == 0 {
 		fmt.Printf("[AssetServer] ⚠️ Image path not found in any file\n")
 		conn.WriteJSON(map[string]interface{}{
 			"status": "error",
 			"error":  "Image path not found in source files",
 		})
 	} else {
-		fmt.Printf("[AssetServer] ✅ Image path replaced successfully\n")
+		totalEdits := 0
+		for _, e := range edits {
+			totalEdits += e.Edits
+		}
+		fmt.Printf("[AssetServer] ✅ Image path replaced successfully (%d edit(s) across %d file(s))\n", totalEdits, len(edits))
 		conn.WriteJSON(map[string]interface{}{
-			"status": "complete",
+			"status":     "complete",
+			"edits":      edits,
+			"totalEdits": totalEdits,
 		})
 	}
 }
 
-// replaceImagePathInFiles searches and replaces image path in all source files
-func (s *Server) replaceImagePathInFiles(oldPath, newPath string) (bool, error) {
-	extensions := []string{".tsx", ".ts", ".jsx", ".js", ".vue", ".svelte"}
-	replaced := false
+// fileEditResult reports how many asset references replaceImagePathInFiles
+// rewrote in a single file, so the browser can show the user what changed
+type fileEditResult struct {
+	File  string `json:"file"`
+	Edits int    `json:"edits"`
+}
+
+// replaceImagePathInFiles rewrites every reference to oldPath as newPath
+// across the source files adapter considers part of this framework's
+// project (its SourceExtensions/IgnoreDirs), via pkg/rewriter's structured
+// rewrite. It only falls back to a raw string replace for a file if the
+// rewriter itself errors, logging a verbose warning when it does, since a
+// silent fallback could corrupt a file the structured pass didn't fully
+// understand.
+func (s *Server) replaceImagePathInFiles(adapter analyzer.FrameworkAdapter, oldPath, newPath string) ([]fileEditResult, error) {
+	extensions := adapter.SourceExtensions()
+	ignoreDirs := adapter.IgnoreDirs()
+	var results []fileEditResult
 
 	err := filepath.Walk(s.projectDir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
@@ -657,8 +916,10 @@ func (s *Server) replaceImagePathInFiles(oldPath, newPath string) (bool, error)
 		}
 
 		if info.IsDir() {
-			if info.Name() == "node_modules" || info.Name() == ".git" || info.Name() == "dist" || info.Name() == "build" {
-				return filepath.SkipDir
+			for _, dir := range ignoreDirs {
+				if info.Name() == dir {
+					return filepath.SkipDir
+				}
 			}
 			return nil
 		}
@@ -674,64 +935,50 @@ func (s *Server) replaceImagePathInFiles(oldPath, newPath string) (bool, error)
 			return nil
 		}
 
-		content, err := os.ReadFile(path)
-		if err != nil {
-			return err
-		}
-
-		contentStr := string(content)
-		if !strings.Contains(contentStr, oldPath) {
+		result, rewriteErr := rewriter.RewriteFile(path, oldPath, newPath)
+		if rewriteErr != nil {
+			if s.verbose {
+				fmt.Printf("[AssetServer] ⚠️  Structured rewrite failed for %s, falling back to raw replace: %v\n", path, rewriteErr)
+			}
+			replaced, fbErr := fallbackReplaceInFile(path, oldPath, newPath, info.Mode())
+			if fbErr != nil {
+				return fbErr
+			}
+			if replaced {
+				fmt.Printf("[AssetServer] ✅ Replaced in: %s\n", path)
+				results = append(results, fileEditResult{File: path, Edits: 1})
+			}
 			return nil
 		}
 
-		fmt.Printf("[AssetServer] 📝 Found in: %s\n", path)
-		newContent := strings.ReplaceAll(contentStr, oldPath, newPath)
-
-		err = os.WriteFile(path, []byte(newContent), info.Mode())
-		if err != nil {
-			return fmt.Errorf("failed to write file %s: %w", path, err)
+		if result.Edits > 0 {
+			fmt.Printf("[AssetServer] 📝 Rewrote %d reference(s) in: %s\n", result.Edits, path)
+			results = append(results, fileEditResult{File: path, Edits: result.Edits})
 		}
-
-		fmt.Printf("[AssetServer] ✅ Replaced in: %s\n", path)
-		replaced = true
 		return nil
 	})
 
-	return replaced, err
+	return results, err
 }
 
-// extractOriginalImagePath extracts the original image path from Next.js optimized URLs
-// E.g., "/_next/image?url=%2Favatar.webp&w=3840&q=75" -> "/avatar.webp"
-func extractOriginalImagePath(path string) string {
-	// Check if this is a Next.js image URL
-	if !strings.Contains(path, "/_next/image") {
-		return path
+// fallbackReplaceInFile does a raw substring replace across the whole file.
+// Only used when pkg/rewriter's structured rewrite errors outright — not
+// when it simply finds no match, which is the common, expected case.
+func fallbackReplaceInFile(path, oldPath, newPath string, mode os.FileMode) (bool, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
 	}
 
-	// Parse URL to extract query parameters
-	if strings.Contains(path, "url=") {
-		// Find url= parameter
-		parts := strings.Split(path, "url=")
-		if len(parts) < 2 {
-			return path
-		}
-
-		// Get the URL-encoded path
-		encodedPath := parts[1]
-
-		// Remove any other query parameters after it
-		if ampIndex := strings.Index(encodedPath, "&"); ampIndex != -1 {
-			encodedPath = encodedPath[:ampIndex]
-		}
-
-		// Decode URL encoding
-		decodedPath := strings.ReplaceAll(encodedPath, "%2F", "/")
-		decodedPath = strings.ReplaceAll(decodedPath, "%2f", "/")
-		decodedPath = strings.ReplaceAll(decodedPath, "%20", " ")
+	contentStr := string(content)
+	if !strings.Contains(contentStr, oldPath) {
+		return false, nil
+	}
 
-		fmt.Printf("[AssetServer] Extracted path from Next.js URL: %s -> %s\n", path, decodedPath)
-		return decodedPath
+	newContent := strings.ReplaceAll(contentStr, oldPath, newPath)
+	if err := os.WriteFile(path, []byte(newContent), mode); err != nil {
+		return false, fmt.Errorf("failed to write file %s: %w", path, err)
 	}
 
-	return path
+	return true, nil
 }
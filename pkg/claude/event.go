@@ -0,0 +1,67 @@
+package claude
+
+import "encoding/json"
+
+// EventType identifies the kind of event emitted by a streaming Claude Code turn
+type EventType string
+
+const (
+	EventContent      EventType = "content"
+	EventToolUse      EventType = "tool_use"
+	EventToolResult   EventType = "tool_result"
+	EventError        EventType = "error"
+	EventTurnComplete EventType = "turn_complete"
+)
+
+// Event is a single parsed unit from Claude Code's stream-json output, normalized
+// so callers don't need to know the shape of the underlying JSONL protocol.
+type Event struct {
+	Type       EventType       `json:"type"`
+	TurnID     string          `json:"turnId"`
+	Content    string          `json:"content,omitempty"`
+	ToolName   string          `json:"toolName,omitempty"`
+	ToolInput  json.RawMessage `json:"toolInput,omitempty"`
+	ToolResult string          `json:"toolResult,omitempty"`
+	Error      string          `json:"error,omitempty"`
+}
+
+// rawEvent mirrors the fields Claude Code's stream-json protocol may emit for a line
+type rawEvent struct {
+	Type    string          `json:"type"`
+	Content string          `json:"content"`
+	Name    string          `json:"name"`
+	Input   json.RawMessage `json:"input"`
+	Error   string          `json:"error"`
+}
+
+// parseEvent converts a single JSONL line into an Event for the given turn
+func parseEvent(turnID, line string) (Event, error) {
+	var raw rawEvent
+	if err := json.Unmarshal([]byte(line), &raw); err != nil {
+		return Event{}, err
+	}
+
+	evt := Event{TurnID: turnID}
+
+	switch raw.Type {
+	case "content":
+		evt.Type = EventContent
+		evt.Content = raw.Content
+	case "tool_use":
+		evt.Type = EventToolUse
+		evt.ToolName = raw.Name
+		evt.ToolInput = raw.Input
+	case "tool_result":
+		evt.Type = EventToolResult
+		evt.ToolResult = raw.Content
+	case "error":
+		evt.Type = EventError
+		evt.Error = raw.Error
+	case "result":
+		evt.Type = EventTurnComplete
+	default:
+		evt.Type = EventType(raw.Type)
+	}
+
+	return evt, nil
+}
@@ -7,9 +7,12 @@ import (
 	"os"
 	"os/exec"
 	"sync"
+
+	"github.com/thetronjohnson/layrr/pkg/config"
 )
 
-// Manager manages Claude Code execution using --print mode
+// Manager manages Claude Code execution, either as one-shot --print calls or
+// as a persistent streaming Session
 type Manager struct {
 	claudePath string
 	projectDir string
@@ -17,6 +20,9 @@ type Manager struct {
 	verbose    bool
 	currentCmd *exec.Cmd
 	cmdMu      sync.Mutex
+
+	sessionMu sync.Mutex
+	session   *Session
 }
 
 // NewManager creates a new manager for Claude Code
@@ -28,13 +34,69 @@ func NewManager(projectDir, claudePath string, verbose bool) (*Manager, error) {
 	}, nil
 }
 
-// SendMessage sends a message to Claude Code using --print mode with streaming JSON output
-func (m *Manager) SendMessage(message string) error {
+// StartSession launches (or returns the already-running) persistent Claude Code
+// session for this manager, resuming the given Claude Code session id if set
+func (m *Manager) StartSession(resumeSessionID string) (*Session, error) {
+	m.sessionMu.Lock()
+	defer m.sessionMu.Unlock()
+
+	if m.session != nil {
+		return m.session, nil
+	}
+
+	session, err := NewSession(m.projectDir, m.claudePath, resumeSessionID, m.verbose)
+	if err != nil {
+		return nil, err
+	}
+
+	m.session = session
+	return session, nil
+}
+
+// CurrentSession returns the persistent session this manager is holding, if
+// StartSession has been called and CloseSession hasn't run since
+func (m *Manager) CurrentSession() *Session {
+	m.sessionMu.Lock()
+	defer m.sessionMu.Unlock()
+	return m.session
+}
+
+// CloseSession stops the persistent session, if one is running, after persisting
+// its Claude Code session id so the next StartSession call for this project can resume it
+func (m *Manager) CloseSession() error {
+	m.sessionMu.Lock()
+	defer m.sessionMu.Unlock()
+
+	if m.session == nil {
+		return nil
+	}
+
+	if sessionID := m.session.SessionID(); sessionID != "" {
+		if err := config.SetProjectSessionID(m.projectDir, sessionID); err != nil {
+			fmt.Printf("[Claude Manager] Warning: failed to persist session id: %v\n", err)
+		}
+	}
+
+	err := m.session.Close()
+	m.session = nil
+	return err
+}
+
+// SendMessage sends a message to Claude Code using --print mode with streaming
+// JSON output, forwarding each parsed event to pw as it arrives. pw may be nil,
+// in which case progress is only logged, not streamed anywhere.
+func (m *Manager) SendMessage(message string, pw ProgressWriter) error {
+	if pw == nil {
+		pw = noopProgressWriter{}
+	}
+
 	fmt.Printf("\n[Claude Manager] 📍 SendMessage called, attempting to acquire lock...\n")
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	fmt.Printf("[Claude Manager] 📍 Lock acquired!\n")
 
+	pw.Status("Starting Claude Code...")
+
 	fmt.Printf("\n[Claude Manager] 🚀 === EXECUTING CLAUDE CODE ===\n")
 	fmt.Printf("[Claude Manager] Working directory: %s\n", m.projectDir)
 	fmt.Printf("[Claude Manager] Claude path: %s\n", m.claudePath)
@@ -58,7 +120,9 @@ func (m *Manager) SendMessage(message string) error {
 	// Pipe stdout to read line-by-line JSONL output
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
-		return fmt.Errorf("failed to create stdout pipe: %w", err)
+		err = fmt.Errorf("failed to create stdout pipe: %w", err)
+		pw.Error(err)
+		return err
 	}
 
 	// Discard stderr to keep terminal clean (only TUI output)
@@ -74,7 +138,9 @@ func (m *Manager) SendMessage(message string) error {
 		m.cmdMu.Lock()
 		m.currentCmd = nil
 		m.cmdMu.Unlock()
-		return fmt.Errorf("failed to start Claude Code: %w", err)
+		err = fmt.Errorf("failed to start Claude Code: %w", err)
+		pw.Error(err)
+		return err
 	}
 
 	// Read and parse JSONL output line by line
@@ -86,7 +152,7 @@ func (m *Manager) SendMessage(message string) error {
 		if m.verbose {
 			fmt.Printf("[Claude Manager] Output line %d: %s\n", lineCount, line)
 		}
-		_ = m.handleStreamLine(line) // Silently skip unparseable lines
+		_ = m.handleStreamLine(line, lineCount, pw) // Silently skip unparseable lines
 	}
 
 	// Wait for command to complete
@@ -99,7 +165,9 @@ func (m *Manager) SendMessage(message string) error {
 
 	if waitErr != nil {
 		fmt.Printf("[Claude Manager] ❌ Command failed with error: %v\n", waitErr)
-		return fmt.Errorf("Claude Code execution failed: %w", waitErr)
+		err := fmt.Errorf("Claude Code execution failed: %w", waitErr)
+		pw.Error(err)
+		return err
 	}
 
 	fmt.Printf("[Claude Manager] ✅ Command completed successfully\n")
@@ -129,8 +197,12 @@ func (m *Manager) Stop() error {
 	return nil
 }
 
-// handleStreamLine parses a single line of JSONL output from Claude Code and logs it
-func (m *Manager) handleStreamLine(line string) error {
+// handleStreamLine parses a single line of JSONL output from Claude Code, logs
+// it, and forwards it to pw as Status/Progress so the caller can stream it on
+// without waiting for SendMessage to return. step is this line's 1-based
+// position in the output so far, reported as Progress's step with total 0
+// (Claude Code doesn't say up front how many steps a turn will take).
+func (m *Manager) handleStreamLine(line string, step int, pw ProgressWriter) error {
 	// Parse the JSON line
 	var event map[string]interface{}
 	if err := json.Unmarshal([]byte(line), &event); err != nil {
@@ -148,18 +220,22 @@ func (m *Manager) handleStreamLine(line string) error {
 	case "content":
 		if content, ok := event["content"].(string); ok {
 			fmt.Printf("[Claude] 💭 %s\n", content)
+			pw.Status(content)
 		}
 	case "tool_use":
 		if toolName, ok := event["name"].(string); ok {
 			fmt.Printf("[Claude] 🔧 Using tool: %s\n", toolName)
+			pw.Progress(step, 0, fmt.Sprintf("Using tool: %s", toolName))
 		}
 	case "tool_result":
 		if result, ok := event["content"].(string); ok {
 			fmt.Printf("[Claude] ✅ Tool result: %s\n", result)
+			pw.Progress(step, 0, result)
 		}
 	case "error":
 		if errMsg, ok := event["error"].(string); ok {
 			fmt.Printf("[Claude] ❌ Error: %s\n", errMsg)
+			pw.Error(fmt.Errorf("%s", errMsg))
 		}
 	default:
 		if m.verbose {
@@ -0,0 +1,28 @@
+package claude
+
+// ProgressWriter receives incremental progress for a Claude Code invocation as
+// it runs, modeled after Docker's streamformatter/NewWriteFlusher pattern:
+// callers get a stream of small, flushable updates instead of blocking for a
+// single final result. Manager.SendMessage calls these as Claude Code's
+// stream-json output arrives; the asset server implements ProgressWriter by
+// serializing each call into a JSON frame over the message WebSocket.
+//
+// It lives in this package, not pkg/bridge, because pkg/bridge imports
+// *Manager and a reverse import would cycle.
+type ProgressWriter interface {
+	// Status reports a free-form status line, e.g. "Starting Claude Code..."
+	Status(msg string)
+	// Progress reports step-based progress. total is 0 when the number of
+	// steps isn't known in advance (the common case for Claude Code turns).
+	Progress(step, total int, msg string)
+	// Error reports a terminal failure of the invocation
+	Error(err error)
+}
+
+// noopProgressWriter discards every call, so SendMessage can be used without
+// a ProgressWriter by callers that don't need streaming updates
+type noopProgressWriter struct{}
+
+func (noopProgressWriter) Status(msg string)                    {}
+func (noopProgressWriter) Progress(step, total int, msg string) {}
+func (noopProgressWriter) Error(err error)                      {}
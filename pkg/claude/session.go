@@ -0,0 +1,227 @@
+package claude
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+// Session is a long-lived Claude Code process communicating over the
+// --input-format stream-json / --output-format stream-json protocol. Unlike
+// Manager's one-shot --print mode, a Session is started once and kept alive
+// across turns so the user's conversation history lives in the CLI process
+// (and can be resumed) rather than being replayed on every message.
+type Session struct {
+	claudePath string
+	projectDir string
+	verbose    bool
+
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout *bufio.Scanner
+
+	sessionID string // set once Claude Code reports it, used for --resume
+
+	mu         sync.Mutex // serializes writes to stdin and turn bookkeeping
+	turnEvents chan Event // events for the in-flight turn, nil when idle
+	turnID     string
+}
+
+// userEnvelope is a single stream-json input line
+type userEnvelope struct {
+	Type    string `json:"type"`
+	TurnID  string `json:"turnId"`
+	Message struct {
+		Role    string `json:"role"`
+		Content string `json:"content"`
+	} `json:"message"`
+}
+
+// cancelEnvelope tells Claude Code to abort the in-flight turn without killing the process
+type cancelEnvelope struct {
+	Type   string `json:"type"`
+	TurnID string `json:"turnId"`
+}
+
+// NewSession launches `claude` once in streaming mode for projectDir. If resumeSessionID
+// is non-empty, Claude Code is asked to resume that prior session's transcript.
+func NewSession(projectDir, claudePath string, resumeSessionID string, verbose bool) (*Session, error) {
+	args := []string{
+		"--input-format", "stream-json",
+		"--output-format", "stream-json",
+		"--verbose",
+		"--dangerously-skip-permissions",
+	}
+	if resumeSessionID != "" {
+		args = append(args, "--resume", resumeSessionID)
+	}
+
+	cmd := exec.Command(claudePath, args...)
+	cmd.Dir = projectDir
+	cmd.Env = os.Environ()
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stdin pipe: %w", err)
+	}
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stdout pipe: %w", err)
+	}
+	cmd.Stderr = nil
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start Claude Code session: %w", err)
+	}
+
+	s := &Session{
+		claudePath: claudePath,
+		projectDir: projectDir,
+		verbose:    verbose,
+		cmd:        cmd,
+		stdin:      stdin,
+		stdout:     bufio.NewScanner(stdout),
+		sessionID:  resumeSessionID,
+	}
+	s.stdout.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	go s.readLoop()
+
+	return s, nil
+}
+
+// readLoop continuously reads JSONL from the Claude Code process and routes each
+// line to whichever turn is currently in flight
+func (s *Session) readLoop() {
+	for s.stdout.Scan() {
+		line := s.stdout.Text()
+		if s.verbose {
+			fmt.Printf("[Claude Session] %s\n", line)
+		}
+
+		s.mu.Lock()
+		turnID := s.turnID
+		events := s.turnEvents
+		s.mu.Unlock()
+
+		if events == nil {
+			continue // no turn is listening, drop the line
+		}
+
+		s.captureSessionID(line)
+
+		evt, err := parseEvent(turnID, line)
+		if err != nil {
+			continue // silently skip unparseable lines, same as Manager.handleStreamLine
+		}
+
+		events <- evt
+
+		if evt.Type == EventTurnComplete || evt.Type == EventError {
+			s.mu.Lock()
+			close(events)
+			s.turnEvents = nil
+			s.turnID = ""
+			s.mu.Unlock()
+		}
+	}
+}
+
+// captureSessionID looks for the session id Claude Code reports so later Sessions
+// can --resume this conversation after a restart
+func (s *Session) captureSessionID(line string) {
+	var probe struct {
+		SessionID string `json:"session_id"`
+	}
+	if err := json.Unmarshal([]byte(line), &probe); err == nil && probe.SessionID != "" {
+		s.mu.Lock()
+		s.sessionID = probe.SessionID
+		s.mu.Unlock()
+	}
+}
+
+// Send writes a user message envelope to the running session and returns a channel
+// of events for that turn. The channel is closed when the turn completes or errors.
+// Only one turn may be in flight at a time.
+func (s *Session) Send(ctx context.Context, message string) (<-chan Event, error) {
+	s.mu.Lock()
+	if s.turnEvents != nil {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("a turn is already in progress, call Cancel before sending another message")
+	}
+
+	turnID := fmt.Sprintf("turn-%d", time.Now().UnixNano())
+	events := make(chan Event, 16)
+	s.turnEvents = events
+	s.turnID = turnID
+	s.mu.Unlock()
+
+	env := userEnvelope{Type: "user", TurnID: turnID}
+	env.Message.Role = "user"
+	env.Message.Content = message
+
+	payload, err := json.Marshal(env)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode message envelope: %w", err)
+	}
+
+	if _, err := s.stdin.Write(append(payload, '\n')); err != nil {
+		return nil, fmt.Errorf("failed to write to Claude Code stdin: %w", err)
+	}
+
+	return events, nil
+}
+
+// Cancel aborts the given turn by writing a cancel envelope, leaving the underlying
+// process (and the rest of the conversation) alive
+func (s *Session) Cancel(turnID string) error {
+	s.mu.Lock()
+	if s.turnID != turnID {
+		s.mu.Unlock()
+		return fmt.Errorf("turn %s is not currently in progress", turnID)
+	}
+	s.mu.Unlock()
+
+	env := cancelEnvelope{Type: "cancel", TurnID: turnID}
+	payload, err := json.Marshal(env)
+	if err != nil {
+		return fmt.Errorf("failed to encode cancel envelope: %w", err)
+	}
+
+	if _, err := s.stdin.Write(append(payload, '\n')); err != nil {
+		return fmt.Errorf("failed to write cancel to Claude Code stdin: %w", err)
+	}
+
+	return nil
+}
+
+// CurrentTurnID returns the turn id of the turn currently in flight, or ""
+// if the session is idle
+func (s *Session) CurrentTurnID() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.turnID
+}
+
+// SessionID returns the Claude Code session id for --resume, empty until the first reply
+func (s *Session) SessionID() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sessionID
+}
+
+// Close stops the session's Claude Code process and closes stdin
+func (s *Session) Close() error {
+	s.stdin.Close()
+	if s.cmd.Process == nil {
+		return nil
+	}
+	return s.cmd.Process.Kill()
+}
@@ -0,0 +1,105 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// BackendConfig persists which AI backend is selected and the credentials each
+// one needs. APIKeys and BaseURLs are keyed by backend name (see pkg/agent),
+// so adding a backend never requires a schema change here.
+type BackendConfig struct {
+	Backend  string            `json:"backend"`
+	APIKeys  map[string]string `json:"apiKeys,omitempty"`
+	BaseURLs map[string]string `json:"baseURLs,omitempty"`
+	Models   map[string]string `json:"models,omitempty"`
+}
+
+// GetBackendConfigPath returns the path to the persisted backend config file
+func GetBackendConfigPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	configDir := filepath.Join(homeDir, ".claude")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		return "", err
+	}
+
+	return filepath.Join(configDir, "layrr-backend.json"), nil
+}
+
+// GetBackendConfig reads the persisted backend config, defaulting to the
+// "claude" backend with no extra credentials if none has been saved yet
+func GetBackendConfig() (BackendConfig, error) {
+	configPath, err := GetBackendConfigPath()
+	if err != nil {
+		return BackendConfig{}, err
+	}
+
+	if _, err := os.Stat(configPath); os.IsNotExist(err) {
+		return BackendConfig{Backend: "claude"}, nil
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return BackendConfig{}, err
+	}
+
+	var cfg BackendConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return BackendConfig{}, err
+	}
+	if cfg.Backend == "" {
+		cfg.Backend = "claude"
+	}
+
+	return cfg, nil
+}
+
+// SetBackendConfig persists the selected backend
+func SetBackendConfig(cfg BackendConfig) error {
+	configPath, err := GetBackendConfigPath()
+	if err != nil {
+		return err
+	}
+
+	jsonData, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(configPath, jsonData, 0644)
+}
+
+// SetBackendCredential saves the API key and/or base URL for a single backend,
+// leaving the rest of the config (including the currently selected backend) intact
+func SetBackendCredential(name, apiKey, baseURL, model string) error {
+	cfg, err := GetBackendConfig()
+	if err != nil {
+		cfg = BackendConfig{Backend: "claude"}
+	}
+	if cfg.APIKeys == nil {
+		cfg.APIKeys = make(map[string]string)
+	}
+	if cfg.BaseURLs == nil {
+		cfg.BaseURLs = make(map[string]string)
+	}
+	if cfg.Models == nil {
+		cfg.Models = make(map[string]string)
+	}
+
+	if apiKey != "" {
+		cfg.APIKeys[name] = apiKey
+	}
+	if baseURL != "" {
+		cfg.BaseURLs[name] = baseURL
+	}
+	if model != "" {
+		cfg.Models[name] = model
+	}
+
+	return SetBackendConfig(cfg)
+}
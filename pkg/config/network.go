@@ -0,0 +1,70 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// NetworkProfileData persists the user's selected network simulation profile
+type NetworkProfileData struct {
+	Name      string  `json:"name"`
+	ReadKBps  int     `json:"readKBps"`
+	WriteKBps int     `json:"writeKBps"`
+	LatencyMs int     `json:"latencyMs"`
+	LossPct   float64 `json:"lossPct"`
+}
+
+// GetNetworkProfilePath returns the path to the persisted network profile config file
+func GetNetworkProfilePath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	configDir := filepath.Join(homeDir, ".claude")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		return "", err
+	}
+
+	return filepath.Join(configDir, "layrr-network-profile.json"), nil
+}
+
+// GetNetworkProfile reads the persisted network profile, if any
+func GetNetworkProfile() (NetworkProfileData, error) {
+	configPath, err := GetNetworkProfilePath()
+	if err != nil {
+		return NetworkProfileData{}, err
+	}
+
+	if _, err := os.Stat(configPath); os.IsNotExist(err) {
+		return NetworkProfileData{Name: "None"}, nil
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return NetworkProfileData{}, err
+	}
+
+	var profile NetworkProfileData
+	if err := json.Unmarshal(data, &profile); err != nil {
+		return NetworkProfileData{}, err
+	}
+
+	return profile, nil
+}
+
+// SetNetworkProfile persists the user's selected network profile
+func SetNetworkProfile(profile NetworkProfileData) error {
+	configPath, err := GetNetworkProfilePath()
+	if err != nil {
+		return err
+	}
+
+	jsonData, err := json.MarshalIndent(profile, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(configPath, jsonData, 0644)
+}
@@ -0,0 +1,120 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// CurrentProjectStateVersion is bumped whenever the ProjectState schema
+// changes in a way LoadProjectState needs to migrate old data for
+const CurrentProjectStateVersion = 1
+
+// Settings holds per-project proxy behavior that the proxy layer reads on
+// startup (see proxy.Settings, which this mirrors field-for-field the same
+// way NetworkProfileData mirrors proxy.NetworkProfile)
+type Settings struct {
+	ExtraHeaders    map[string]string `json:"extraHeaders,omitempty"`
+	HeaderOverrides map[string]string `json:"headerOverrides,omitempty"`
+	CookieOverrides map[string]string `json:"cookieOverrides,omitempty"`
+	AllowedHosts    []string          `json:"allowedHosts,omitempty"`
+}
+
+// SidebarState captures what the sidebar was showing when the project was
+// last closed, so reopening it restores the same view
+type SidebarState struct {
+	Layout          string   `json:"layout,omitempty"`
+	SelectedElement string   `json:"selectedElement,omitempty"`
+	LastRoute       string   `json:"lastRoute,omitempty"`
+	Annotations     []string `json:"annotations,omitempty"`
+}
+
+// ProjectState is the full persisted state for a single project, stored at
+// the path returned by GetProjectStatePath. RecentProjectsData remains a
+// lightweight index over these files; this is where per-project detail lives.
+type ProjectState struct {
+	Version  int          `json:"version"`
+	Path     string       `json:"path"`
+	Sidebar  SidebarState `json:"sidebar,omitempty"`
+	Settings Settings     `json:"settings,omitempty"`
+}
+
+// projectStateHash derives the stable, filesystem-safe filename a project's
+// state is stored under from its absolute path, so renames of the config
+// directory (or path casing differences) can't collide two projects
+func projectStateHash(projectDir string) string {
+	sum := sha256.Sum256([]byte(filepath.Clean(projectDir)))
+	return hex.EncodeToString(sum[:])
+}
+
+// GetProjectStatePath returns the path to projectDir's persisted ProjectState
+func GetProjectStatePath(projectDir string) (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	stateDir := filepath.Join(homeDir, ".claude", "layrr", "projects")
+	if err := os.MkdirAll(stateDir, 0755); err != nil {
+		return "", err
+	}
+
+	return filepath.Join(stateDir, projectStateHash(projectDir)+".json"), nil
+}
+
+// LoadProjectState reads the persisted state for projectDir. A project that
+// predates per-project state files (tracked only in the RecentProjectsData
+// index, which still owns Path/Name/LastOpened/TargetPort) has no file yet
+// and gets a fresh zero-value state on version CurrentProjectStateVersion.
+func LoadProjectState(projectDir string) (ProjectState, error) {
+	statePath, err := GetProjectStatePath(projectDir)
+	if err != nil {
+		return ProjectState{}, err
+	}
+
+	if _, err := os.Stat(statePath); os.IsNotExist(err) {
+		return ProjectState{Version: CurrentProjectStateVersion, Path: projectDir}, nil
+	}
+
+	data, err := os.ReadFile(statePath)
+	if err != nil {
+		return ProjectState{}, err
+	}
+
+	var state ProjectState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return ProjectState{}, err
+	}
+
+	return migrateProjectState(state), nil
+}
+
+// migrateProjectState upgrades an on-disk ProjectState to
+// CurrentProjectStateVersion. There's only one version so far; this is the
+// seam future schema changes hang their migration steps on.
+func migrateProjectState(state ProjectState) ProjectState {
+	if state.Version < 1 {
+		state.Version = 1
+	}
+	return state
+}
+
+// SaveProjectState persists the full state for projectDir
+func SaveProjectState(projectDir string, state ProjectState) error {
+	statePath, err := GetProjectStatePath(projectDir)
+	if err != nil {
+		return err
+	}
+
+	state.Version = CurrentProjectStateVersion
+	state.Path = projectDir
+
+	jsonData, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(statePath, jsonData, 0644)
+}
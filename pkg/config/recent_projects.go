@@ -7,7 +7,13 @@ import (
 	"time"
 )
 
-// RecentProject represents a recently opened project
+// defaultMaxRecentProjects is used until the user saves their own MaxRecent
+const defaultMaxRecentProjects = 10
+
+// RecentProject is an index entry pointing at a project's full ProjectState
+// file; see GetProjectStatePath. The fields here are duplicated from that
+// state (rather than read from disk) so the recent-projects list stays cheap
+// to load on startup.
 type RecentProject struct {
 	Path       string    `json:"path"`
 	Name       string    `json:"name"`
@@ -39,37 +45,53 @@ func GetRecentProjectsPath() (string, error) {
 
 // GetRecentProjects reads the recent projects list from disk
 func GetRecentProjects() ([]RecentProject, error) {
-	configPath, err := GetRecentProjectsPath()
+	data, err := readRecentProjectsData()
 	if err != nil {
 		return []RecentProject{}, err
 	}
+	return data.RecentProjects, nil
+}
 
-	// If file doesn't exist, return empty list
-	if _, err := os.Stat(configPath); os.IsNotExist(err) {
-		return []RecentProject{}, nil
+// GetMaxRecentProjects returns the user's configured recent-projects limit,
+// defaulting to defaultMaxRecentProjects if none has been saved yet
+func GetMaxRecentProjects() (int, error) {
+	data, err := readRecentProjectsData()
+	if err != nil {
+		return defaultMaxRecentProjects, err
 	}
+	return data.MaxRecent, nil
+}
 
-	data, err := os.ReadFile(configPath)
+// SetMaxRecentProjects saves the user's recent-projects limit, trimming the
+// existing list down to it immediately
+func SetMaxRecentProjects(maxRecent int) error {
+	if maxRecent < 1 {
+		maxRecent = 1
+	}
+
+	data, err := readRecentProjectsData()
 	if err != nil {
-		return []RecentProject{}, err
+		data = RecentProjectsData{MaxRecent: defaultMaxRecentProjects}
 	}
 
-	var projectsData RecentProjectsData
-	if err := json.Unmarshal(data, &projectsData); err != nil {
-		return []RecentProject{}, err
+	data.MaxRecent = maxRecent
+	if len(data.RecentProjects) > maxRecent {
+		data.RecentProjects = data.RecentProjects[:maxRecent]
 	}
 
-	return projectsData.RecentProjects, nil
+	return saveRecentProjects(data)
 }
 
 // AddRecentProject adds or updates a project in the recent projects list
 func AddRecentProject(path, name string, targetPort int) error {
-	projects, err := GetRecentProjects()
+	data, err := readRecentProjectsData()
 	if err != nil {
 		// If we can't read, start fresh
-		projects = []RecentProject{}
+		data = RecentProjectsData{MaxRecent: defaultMaxRecentProjects}
 	}
 
+	projects := data.RecentProjects
+
 	// Check if project already exists
 	existingIndex := -1
 	for i, p := range projects {
@@ -94,42 +116,59 @@ func AddRecentProject(path, name string, targetPort int) error {
 	// Add to front of list
 	projects = append([]RecentProject{newProject}, projects...)
 
-	// Limit to max 10 recent projects
-	maxRecent := 10
-	if len(projects) > maxRecent {
-		projects = projects[:maxRecent]
-	}
-
-	// Save to disk
-	projectsData := RecentProjectsData{
-		RecentProjects: projects,
-		MaxRecent:      maxRecent,
+	// Limit to the configured number of recent projects
+	if len(projects) > data.MaxRecent {
+		projects = projects[:data.MaxRecent]
 	}
 
-	return saveRecentProjects(projectsData)
+	data.RecentProjects = projects
+	return saveRecentProjects(data)
 }
 
 // RemoveRecentProject removes a project from the recent projects list
 func RemoveRecentProject(path string) error {
-	projects, err := GetRecentProjects()
+	data, err := readRecentProjectsData()
 	if err != nil {
 		return err
 	}
 
-	// Find and remove the project
 	filteredProjects := []RecentProject{}
-	for _, p := range projects {
+	for _, p := range data.RecentProjects {
 		if p.Path != path {
 			filteredProjects = append(filteredProjects, p)
 		}
 	}
 
-	projectsData := RecentProjectsData{
-		RecentProjects: filteredProjects,
-		MaxRecent:      10,
+	data.RecentProjects = filteredProjects
+	return saveRecentProjects(data)
+}
+
+// readRecentProjectsData reads the full recent projects store, defaulting
+// MaxRecent to defaultMaxRecentProjects if it hasn't been set yet
+func readRecentProjectsData() (RecentProjectsData, error) {
+	configPath, err := GetRecentProjectsPath()
+	if err != nil {
+		return RecentProjectsData{MaxRecent: defaultMaxRecentProjects}, err
+	}
+
+	if _, err := os.Stat(configPath); os.IsNotExist(err) {
+		return RecentProjectsData{MaxRecent: defaultMaxRecentProjects}, nil
+	}
+
+	raw, err := os.ReadFile(configPath)
+	if err != nil {
+		return RecentProjectsData{MaxRecent: defaultMaxRecentProjects}, err
+	}
+
+	var data RecentProjectsData
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return RecentProjectsData{MaxRecent: defaultMaxRecentProjects}, err
+	}
+	if data.MaxRecent == 0 {
+		data.MaxRecent = defaultMaxRecentProjects
 	}
 
-	return saveRecentProjects(projectsData)
+	return data, nil
 }
 
 // saveRecentProjects writes the recent projects data to disk
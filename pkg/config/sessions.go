@@ -0,0 +1,89 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// SessionsData maps a project directory to the Claude Code session id that
+// should be resumed with `--resume` the next time that project is opened
+type SessionsData struct {
+	Sessions map[string]string `json:"sessions"`
+}
+
+// GetSessionsPath returns the path to the per-project session id store
+func GetSessionsPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	configDir := filepath.Join(homeDir, ".claude")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		return "", err
+	}
+
+	return filepath.Join(configDir, "layrr-sessions.json"), nil
+}
+
+// GetProjectSessionID returns the last known Claude Code session id for projectDir,
+// or an empty string if none has been recorded
+func GetProjectSessionID(projectDir string) (string, error) {
+	data, err := readSessionsData()
+	if err != nil {
+		return "", err
+	}
+	return data.Sessions[projectDir], nil
+}
+
+// SetProjectSessionID records the Claude Code session id to resume for projectDir
+func SetProjectSessionID(projectDir, sessionID string) error {
+	data, err := readSessionsData()
+	if err != nil {
+		data = SessionsData{Sessions: make(map[string]string)}
+	}
+
+	data.Sessions[projectDir] = sessionID
+	return saveSessionsData(data)
+}
+
+func readSessionsData() (SessionsData, error) {
+	configPath, err := GetSessionsPath()
+	if err != nil {
+		return SessionsData{}, err
+	}
+
+	if _, err := os.Stat(configPath); os.IsNotExist(err) {
+		return SessionsData{Sessions: make(map[string]string)}, nil
+	}
+
+	raw, err := os.ReadFile(configPath)
+	if err != nil {
+		return SessionsData{}, err
+	}
+
+	var data SessionsData
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return SessionsData{}, err
+	}
+	if data.Sessions == nil {
+		data.Sessions = make(map[string]string)
+	}
+
+	return data, nil
+}
+
+func saveSessionsData(data SessionsData) error {
+	configPath, err := GetSessionsPath()
+	if err != nil {
+		return err
+	}
+
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(configPath, jsonData, 0644)
+}
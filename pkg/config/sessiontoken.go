@@ -0,0 +1,45 @@
+package config
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// GetSessionTokenPath returns the path to the per-project session auth
+// token, stored alongside the project (like .git) rather than under the
+// user's global ~/.claude config, since it authenticates only this
+// particular checkout's running proxy instance
+func GetSessionTokenPath(projectDir string) (string, error) {
+	dir := filepath.Join(projectDir, ".layrr")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "session"), nil
+}
+
+// NewSessionToken mints a fresh random token for this run of the proxy
+// against projectDir and persists it to GetSessionTokenPath, overwriting any
+// token left over from a previous run. The asset server requires this token
+// on its WebSocket and REST endpoints so that another page open in the same
+// browser can't drive it without having read this file.
+func NewSessionToken(projectDir string) (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate session token: %w", err)
+	}
+	token := hex.EncodeToString(buf)
+
+	path, err := GetSessionTokenPath(projectDir)
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, []byte(token), 0600); err != nil {
+		return "", fmt.Errorf("failed to persist session token: %w", err)
+	}
+
+	return token, nil
+}
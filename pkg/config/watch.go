@@ -0,0 +1,96 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// WatchOptionsData persists per-project file watcher configuration beyond the
+// default .gitignore-driven behavior
+type WatchOptionsData struct {
+	ExtraIgnores    []string `json:"extraIgnores,omitempty"`
+	ExtraExtensions []string `json:"extraExtensions,omitempty"`
+	DebounceMs      int      `json:"debounceMs,omitempty"`
+}
+
+// watchOptionsStore maps a project directory to its saved watch options
+type watchOptionsStore struct {
+	Projects map[string]WatchOptionsData `json:"projects"`
+}
+
+// GetWatchOptionsPath returns the path to the per-project watch options store
+func GetWatchOptionsPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	configDir := filepath.Join(homeDir, ".claude")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		return "", err
+	}
+
+	return filepath.Join(configDir, "layrr-watch-options.json"), nil
+}
+
+// GetWatchOptions returns the saved watch options for projectDir, or the zero
+// value (pure .gitignore behavior, 300ms debounce) if none have been saved
+func GetWatchOptions(projectDir string) (WatchOptionsData, error) {
+	store, err := readWatchOptionsStore()
+	if err != nil {
+		return WatchOptionsData{}, err
+	}
+	return store.Projects[projectDir], nil
+}
+
+// SetWatchOptions saves the watch options for projectDir
+func SetWatchOptions(projectDir string, opts WatchOptionsData) error {
+	store, err := readWatchOptionsStore()
+	if err != nil {
+		store = watchOptionsStore{Projects: make(map[string]WatchOptionsData)}
+	}
+
+	store.Projects[projectDir] = opts
+	return saveWatchOptionsStore(store)
+}
+
+func readWatchOptionsStore() (watchOptionsStore, error) {
+	configPath, err := GetWatchOptionsPath()
+	if err != nil {
+		return watchOptionsStore{}, err
+	}
+
+	if _, err := os.Stat(configPath); os.IsNotExist(err) {
+		return watchOptionsStore{Projects: make(map[string]WatchOptionsData)}, nil
+	}
+
+	raw, err := os.ReadFile(configPath)
+	if err != nil {
+		return watchOptionsStore{}, err
+	}
+
+	var store watchOptionsStore
+	if err := json.Unmarshal(raw, &store); err != nil {
+		return watchOptionsStore{}, err
+	}
+	if store.Projects == nil {
+		store.Projects = make(map[string]WatchOptionsData)
+	}
+
+	return store, nil
+}
+
+func saveWatchOptionsStore(store watchOptionsStore) error {
+	configPath, err := GetWatchOptionsPath()
+	if err != nil {
+		return err
+	}
+
+	jsonData, err := json.MarshalIndent(store, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(configPath, jsonData, 0644)
+}
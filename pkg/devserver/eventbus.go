@@ -0,0 +1,50 @@
+package devserver
+
+import "sync"
+
+// eventBus fans a lifecycle Event out to any number of live subscribers (the
+// Wails frontend, the /__layrr/ws/events multiplexer, ...), mirroring the
+// logBuffer subscribe/unsubscribe pattern used for stdout/stderr streaming.
+type eventBus struct {
+	mu          sync.Mutex
+	subscribers map[chan Event]bool
+}
+
+func newEventBus() *eventBus {
+	return &eventBus{subscribers: make(map[chan Event]bool)}
+}
+
+// publish forwards evt to every subscriber, dropping it for anyone whose
+// buffer is full rather than blocking the supervisor loop
+func (b *eventBus) publish(evt Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subscribers {
+		select {
+		case ch <- evt:
+		default:
+		}
+	}
+}
+
+// subscribe returns a channel that receives every event published after this call
+func (b *eventBus) subscribe() chan Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ch := make(chan Event, 32)
+	b.subscribers[ch] = true
+	return ch
+}
+
+// unsubscribe stops a channel returned by subscribe from receiving further events
+func (b *eventBus) unsubscribe(ch chan Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.subscribers[ch]; ok {
+		delete(b.subscribers, ch)
+		close(ch)
+	}
+}
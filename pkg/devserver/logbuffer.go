@@ -0,0 +1,71 @@
+package devserver
+
+import "sync"
+
+// logBuffer is a fixed-capacity ring buffer of captured stdout/stderr lines so
+// late subscribers (e.g. a freshly opened log panel) still get recent output
+// instead of only lines emitted after they started watching. It also fans new
+// lines out to live subscribers (e.g. a log-streaming WebSocket).
+type logBuffer struct {
+	mu          sync.Mutex
+	lines       []string
+	max         int
+	subscribers map[chan string]bool
+}
+
+func newLogBuffer(maxLines int) *logBuffer {
+	return &logBuffer{max: maxLines, subscribers: make(map[chan string]bool)}
+}
+
+// Append adds a line to the buffer, evicting the oldest line if at capacity,
+// and forwards it to any live subscribers
+func (b *logBuffer) Append(line string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lines = append(b.lines, line)
+	if len(b.lines) > b.max {
+		b.lines = b.lines[len(b.lines)-b.max:]
+	}
+
+	for ch := range b.subscribers {
+		select {
+		case ch <- line:
+		default: // slow subscriber, drop the line rather than block the dev server
+		}
+	}
+}
+
+// Subscribe returns a channel that receives each new line as it's appended.
+// Call Unsubscribe when done to release it.
+func (b *logBuffer) Subscribe() chan string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ch := make(chan string, 64)
+	b.subscribers[ch] = true
+	return ch
+}
+
+// Unsubscribe stops a channel returned by Subscribe from receiving further lines
+func (b *logBuffer) Unsubscribe(ch chan string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.subscribers, ch)
+	close(ch)
+}
+
+// Last returns the most recent n lines, or all lines if n <= 0 or exceeds the buffer
+func (b *logBuffer) Last(n int) []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if n <= 0 || n > len(b.lines) {
+		n = len(b.lines)
+	}
+
+	out := make([]string, n)
+	copy(out, b.lines[len(b.lines)-n:])
+	return out
+}
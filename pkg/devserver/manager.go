@@ -1,25 +1,76 @@
 package devserver
 
 import (
+	"bufio"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net"
+	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/thetronjohnson/layrr/pkg/proxy"
+	"github.com/thetronjohnson/layrr/pkg/proxy/netstat"
 )
 
+const (
+	// defaultStartRetries is how many times the supervisor restarts a crashing
+	// process before giving up and transitioning to StateFatal
+	defaultStartRetries = 5
+
+	// defaultStartSeconds is how long a process must stay up before an exit no
+	// longer counts as a "quick failure" that consumes a restart attempt
+	defaultStartSeconds = 10 * time.Second
+
+	// defaultBackoffBase/Max bound the exponential backoff between restarts
+	defaultBackoffBase = 500 * time.Millisecond
+	defaultBackoffMax  = 30 * time.Second
+
+	// defaultGraceTimeout is how long a SIGTERM'd process group gets to exit
+	// on its own before Stop escalates to SIGKILL
+	defaultGraceTimeout = 5 * time.Second
+
+	// logBufferLines bounds how much captured stdout/stderr is kept for late subscribers
+	logBufferLines = 2000
+
+	// healthCheckInterval is how often the liveness probe HEADs the detected port
+	healthCheckInterval = 5 * time.Second
+)
+
+// Manager supervises a project's dev server process: it starts it, watches for
+// crashes, and restarts it with exponential backoff up to StartRetries times
+// before giving up, modeled on supervisord's Starting/Running/Backoff/Fatal states.
 type Manager struct {
 	projectDir     string
-	cmd            *exec.Cmd
 	port           int
 	packageManager string
 	devScript      string
 	existingPorts  map[int]bool // Ports that were already open before we started
+
+	StartRetries int           // max restart attempts after a quick failure, 0 uses the default
+	StartSeconds time.Duration // uptime threshold below which an exit counts as a failure
+	GraceTimeout time.Duration // how long Stop waits after SIGTERM before escalating, 0 uses the default
+
+	// stateMu guards every field the supervise()/probeHealth() goroutines and
+	// callers like StopWithContext/WaitForPort touch concurrently: state
+	// itself, the running cmd, the stop flag, and the detected port. cmd and
+	// waitDone in particular are replaced wholesale on every restart, and port
+	// is written by WaitForPort on the caller's goroutine while probeHealth
+	// reads it on its own, so none of this is safe unguarded.
+	stateMu       sync.Mutex
+	state         State
+	cmd           *exec.Cmd
+	stopRequested bool
+	waitDone      chan struct{} // closed once the current cmd.Wait() in supervise() returns
+
+	events *eventBus
+	logs   *logBuffer
 }
 
 type packageJSON struct {
@@ -29,10 +80,110 @@ type packageJSON struct {
 // NewManager creates a new dev server manager for the given project
 func NewManager(projectDir string) *Manager {
 	return &Manager{
-		projectDir: projectDir,
+		projectDir:   projectDir,
+		StartRetries: defaultStartRetries,
+		StartSeconds: defaultStartSeconds,
+		GraceTimeout: defaultGraceTimeout,
+		state:        StateStopped,
+		events:       newEventBus(),
+		logs:         newLogBuffer(logBufferLines),
 	}
 }
 
+// Subscribe returns a channel that receives every lifecycle state transition
+// as it happens. Call Unsubscribe with the same channel when done.
+func (m *Manager) Subscribe() chan Event {
+	return m.events.subscribe()
+}
+
+// Unsubscribe releases a channel returned by Subscribe
+func (m *Manager) Unsubscribe(ch chan Event) {
+	m.events.unsubscribe(ch)
+}
+
+// State returns the dev server's current supervisor state
+func (m *Manager) State() State {
+	m.stateMu.Lock()
+	defer m.stateMu.Unlock()
+	return m.state
+}
+
+// GetLogs returns the last n captured stdout/stderr lines, or all buffered lines if n <= 0
+func (m *Manager) GetLogs(n int) []string {
+	return m.logs.Last(n)
+}
+
+// SubscribeLogs returns a channel of new stdout/stderr lines as they're captured.
+// Call UnsubscribeLogs with the same channel when the caller disconnects.
+func (m *Manager) SubscribeLogs() chan string {
+	return m.logs.Subscribe()
+}
+
+// UnsubscribeLogs releases a channel returned by SubscribeLogs
+func (m *Manager) UnsubscribeLogs(ch chan string) {
+	m.logs.Unsubscribe(ch)
+}
+
+// setState updates the supervisor state and emits an Event, dropping it for
+// any subscriber whose buffer is full rather than blocking the supervisor loop
+func (m *Manager) setState(state State, attempt int, message string) {
+	m.stateMu.Lock()
+	m.state = state
+	m.stateMu.Unlock()
+
+	evt := Event{State: state, Port: m.currentPort(), Attempt: attempt, Message: message}
+	m.events.publish(evt)
+}
+
+// setPort records the port WaitForPort detected, guarded by stateMu since
+// probeHealth reads it concurrently on its own goroutine
+func (m *Manager) setPort(port int) {
+	m.stateMu.Lock()
+	m.port = port
+	m.stateMu.Unlock()
+}
+
+// currentPort returns the port WaitForPort last detected, or 0 if none yet
+func (m *Manager) currentPort() int {
+	m.stateMu.Lock()
+	defer m.stateMu.Unlock()
+	return m.port
+}
+
+// setCmd records the process startProcess just spawned and its wait-done
+// channel, guarded by stateMu since supervise() replaces both on every
+// restart while StopWithContext may be reading them concurrently.
+func (m *Manager) setCmd(cmd *exec.Cmd, waitDone chan struct{}) {
+	m.stateMu.Lock()
+	m.cmd = cmd
+	m.waitDone = waitDone
+	m.stateMu.Unlock()
+}
+
+// currentCmd returns the process supervise() is currently watching and its
+// wait-done channel
+func (m *Manager) currentCmd() (*exec.Cmd, chan struct{}) {
+	m.stateMu.Lock()
+	defer m.stateMu.Unlock()
+	return m.cmd, m.waitDone
+}
+
+// setStopRequested records whether the supervisor should stop restarting the
+// process on its next exit
+func (m *Manager) setStopRequested(v bool) {
+	m.stateMu.Lock()
+	m.stopRequested = v
+	m.stateMu.Unlock()
+}
+
+// isStopRequested reports whether Stop/StopWithContext has been called since
+// the last Start
+func (m *Manager) isStopRequested() bool {
+	m.stateMu.Lock()
+	defer m.stateMu.Unlock()
+	return m.stopRequested
+}
+
 // DetectPackageManager detects which package manager the project uses
 func (m *Manager) DetectPackageManager() (string, error) {
 	// Check for lock files in priority order: bun > pnpm > yarn > npm
@@ -98,43 +249,62 @@ func (m *Manager) killExistingServers() {
 	}
 }
 
-// killProcessOnPort kills the process listening on the given port and waits for port to close
+// killProcessOnPort gracefully shuts down the process listening on the given
+// port: terminate first, escalate to a forced kill if it's still holding the
+// port after GraceTimeout.
 func (m *Manager) killProcessOnPort(port int) {
-	// Use lsof to find the LISTENING process only (not clients connected to it)
-	cmd := exec.Command("lsof", "-ti", fmt.Sprintf(":%d", port), "-sTCP:LISTEN")
-	output, err := cmd.Output()
+	// Find the LISTENING process only (not clients connected to it) via the
+	// pure-Go socket table reader, so this works without lsof/netstat installed
+	listeners, err := netstat.ListListeners()
 	if err != nil {
 		return
 	}
 
-	// Kill the process - IMPORTANT: trim whitespace/newlines from PID
-	pid := strings.TrimSpace(string(output))
-	if len(pid) > 0 {
-		killCmd := exec.Command("kill", "-9", pid)
-		if err := killCmd.Run(); err != nil {
-			fmt.Printf("Warning: Failed to kill process %s: %v\n", pid, err)
-			return
+	pid := 0
+	for _, l := range listeners {
+		if l.Port == port {
+			pid = l.PID
+			break
 		}
+	}
+	if pid == 0 {
+		return
+	}
 
-		// Wait for the port to actually close (max 5 seconds)
-		for i := 0; i < 50; i++ {
-			time.Sleep(100 * time.Millisecond)
+	if err := terminatePID(pid); err != nil {
+		return
+	}
 
-			// Check if port is still open
-			addr := fmt.Sprintf("localhost:%d", port)
-			conn, err := net.DialTimeout("tcp", addr, 100*time.Millisecond)
-			if err != nil {
-				// Port is closed, success!
-				return
-			}
-			conn.Close()
-		}
+	if m.waitForPortClosed(port, m.graceTimeout()) {
+		return
+	}
+
+	killPID(pid)
+
+	if !m.waitForPortClosed(port, 5*time.Second) {
+		fmt.Printf("Warning: Port %d still open after grace timeout and forced kill\n", port)
+	}
+}
+
+// waitForPortClosed polls until nothing answers on port or timeout elapses,
+// returning whether the port closed in time
+func (m *Manager) waitForPortClosed(port int, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		time.Sleep(100 * time.Millisecond)
 
-		fmt.Printf("Warning: Port %d still open after 5 seconds\n", port)
+		addr := fmt.Sprintf("localhost:%d", port)
+		conn, err := net.DialTimeout("tcp", addr, 100*time.Millisecond)
+		if err != nil {
+			return true
+		}
+		conn.Close()
 	}
+	return false
 }
 
-// Start starts the dev server
+// Start starts the dev server and a supervisor goroutine that restarts it with
+// exponential backoff if it crashes, up to StartRetries attempts
 func (m *Manager) Start() error {
 	// Detect package manager
 	pm, err := m.DetectPackageManager()
@@ -165,19 +335,170 @@ func (m *Manager) Start() error {
 	// Don't kill existing servers anymore - we just want to detect NEW ports
 	// m.killExistingServers()
 
-	// Start the dev server
-	m.cmd = exec.Command(m.packageManager, "run", "dev")
-	m.cmd.Dir = m.projectDir
-	m.cmd.Env = os.Environ()
+	m.setStopRequested(false)
+	if err := m.startProcess(); err != nil {
+		return err
+	}
+
+	go m.supervise()
+	go m.probeHealth()
+
+	return nil
+}
+
+// startProcess spawns the package manager's dev script and streams its
+// stdout/stderr into the ring-buffered log store
+func (m *Manager) startProcess() error {
+	m.setState(StateStarting, 0, "starting dev server")
+
+	cmd := exec.Command(m.packageManager, "run", "dev")
+	cmd.Dir = m.projectDir
+	cmd.Env = os.Environ()
+	setProcessGroup(cmd) // so a single SIGTERM/taskkill reaches every child it spawns
 
-	// Start the process
-	if err := m.cmd.Start(); err != nil {
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("failed to create stdout pipe: %w", err)
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return fmt.Errorf("failed to create stderr pipe: %w", err)
+	}
+
+	if err := cmd.Start(); err != nil {
 		return fmt.Errorf("failed to start dev server: %w", err)
 	}
 
+	m.setCmd(cmd, make(chan struct{}))
+	go m.captureOutput(stdout)
+	go m.captureOutput(stderr)
+
 	return nil
 }
 
+// captureOutput appends each line from the dev server's stdout/stderr to the log buffer
+func (m *Manager) captureOutput(r io.Reader) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		m.logs.Append(scanner.Text())
+	}
+}
+
+// supervise watches the current process and restarts it with exponential backoff
+// on a crash, counting only exits that happen before StartSeconds has elapsed
+func (m *Manager) supervise() {
+	attempt := 0
+
+	for {
+		cmd, waitDone := m.currentCmd()
+		startedAt := time.Now()
+
+		m.setState(StateRunning, attempt, "dev server running")
+		waitErr := cmd.Wait()
+		close(waitDone)
+
+		if m.isStopRequested() {
+			m.setState(StateStopped, attempt, "dev server stopped")
+			return
+		}
+
+		quickFailure := time.Since(startedAt) < m.startSeconds()
+		if quickFailure {
+			attempt++
+		} else {
+			attempt = 0
+		}
+
+		if attempt > m.startRetries() {
+			m.setState(StateFatal, attempt, fmt.Sprintf("dev server failed to stay up after %d attempts: %v", attempt, waitErr))
+			return
+		}
+
+		backoff := m.backoffFor(attempt)
+		m.setState(StateBackoff, attempt, fmt.Sprintf("restarting in %s after: %v", backoff, waitErr))
+		time.Sleep(backoff)
+
+		if m.isStopRequested() {
+			m.setState(StateStopped, attempt, "dev server stopped")
+			return
+		}
+
+		if err := m.startProcess(); err != nil {
+			m.setState(StateFatal, attempt, fmt.Sprintf("failed to restart dev server: %v", err))
+			return
+		}
+	}
+}
+
+// probeHealth periodically issues an HTTP HEAD against the detected port and
+// marks the server unhealthy if it stops responding, clearing back to
+// StateRunning once it responds again. It only ever moves between
+// StateRunning and StateUnhealthy, leaving StateStarting/StateBackoff/
+// StateFatal alone so it never papers over - or gets confused with -
+// supervise()'s own restart-driven transitions.
+func (m *Manager) probeHealth() {
+	ticker := time.NewTicker(healthCheckInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if m.isStopRequested() {
+			return
+		}
+		port := m.currentPort()
+		if port == 0 {
+			continue
+		}
+
+		url := fmt.Sprintf("http://localhost:%d/", port)
+		client := http.Client{Timeout: 2 * time.Second}
+		resp, err := client.Head(url)
+		if err != nil {
+			if state := m.State(); state == StateRunning || state == StateUnhealthy {
+				m.setState(StateUnhealthy, 0, fmt.Sprintf("liveness probe failed: %v", err))
+			}
+			continue
+		}
+		resp.Body.Close()
+
+		if m.State() == StateUnhealthy {
+			m.setState(StateRunning, 0, "liveness probe recovered")
+		}
+	}
+}
+
+func (m *Manager) startRetries() int {
+	if m.StartRetries > 0 {
+		return m.StartRetries
+	}
+	return defaultStartRetries
+}
+
+func (m *Manager) startSeconds() time.Duration {
+	if m.StartSeconds > 0 {
+		return m.StartSeconds
+	}
+	return defaultStartSeconds
+}
+
+func (m *Manager) graceTimeout() time.Duration {
+	if m.GraceTimeout > 0 {
+		return m.GraceTimeout
+	}
+	return defaultGraceTimeout
+}
+
+// backoffFor returns the exponential backoff delay for the given attempt, capped at defaultBackoffMax
+func (m *Manager) backoffFor(attempt int) time.Duration {
+	delay := defaultBackoffBase
+	for i := 0; i < attempt; i++ {
+		delay *= 2
+		if delay >= defaultBackoffMax {
+			return defaultBackoffMax
+		}
+	}
+	return delay
+}
+
 // WaitForPort waits for the dev server to start and returns the port
 func (m *Manager) WaitForPort(timeout time.Duration) (int, error) {
 	start := time.Now()
@@ -212,7 +533,7 @@ func (m *Manager) WaitForPort(timeout time.Duration) (int, error) {
 			// Check if the process is running in our project directory
 			if normalizedWorkDir == normalizedProjectDir || strings.HasPrefix(normalizedWorkDir, normalizedProjectDir+string(filepath.Separator)) {
 				// This is a NEW port from our project!
-				m.port = port
+				m.setPort(port)
 				return port, nil
 			}
 		}
@@ -224,24 +545,43 @@ func (m *Manager) WaitForPort(timeout time.Duration) (int, error) {
 
 // GetPort returns the port the dev server is running on
 func (m *Manager) GetPort() int {
-	return m.port
+	return m.currentPort()
 }
 
-// Stop stops the dev server
+// Stop stops the dev server, giving it GraceTimeout to shut down cleanly
+// before escalating to a forced kill. Equivalent to StopWithContext with a
+// context that's never cancelled early.
 func (m *Manager) Stop() error {
-	// First, kill the process listening on the port (the actual dev server)
-	if m.port > 0 {
-		m.killProcessOnPort(m.port)
-	}
+	return m.StopWithContext(context.Background())
+}
 
-	// Then kill the parent process (npm/bun/pnpm) as cleanup
-	if m.cmd != nil && m.cmd.Process != nil {
-		if err := m.cmd.Process.Kill(); err != nil {
-			// Don't return error if parent is already dead
-			// (it might have been killed when we killed the port process)
-			return nil
+// StopWithContext stops the dev server: SIGTERM (taskkill on Windows) to its
+// entire process group, then waits for GraceTimeout or ctx cancellation,
+// whichever comes first, before escalating to SIGKILL/taskkill /F on
+// whatever's left. Signalling the whole group - not just the process on the
+// port - is what lets npm/bun/pnpm and the dev server it spawned exit
+// together instead of the parent being orphaned.
+func (m *Manager) StopWithContext(ctx context.Context) error {
+	// Tell the supervisor not to restart the process it's about to see exit
+	m.setStopRequested(true)
+
+	if cmd, waitDone := m.currentCmd(); cmd != nil && cmd.Process != nil {
+		if err := terminateProcessGroup(cmd); err == nil {
+			select {
+			case <-waitDone:
+			case <-time.After(m.graceTimeout()):
+				killProcessGroup(cmd)
+			case <-ctx.Done():
+				killProcessGroup(cmd)
+			}
 		}
 	}
 
+	// Catch anything still holding the port - e.g. a process that predates
+	// this Manager and was never part of its group
+	if port := m.currentPort(); port > 0 {
+		m.killProcessOnPort(port)
+	}
+
 	return nil
 }
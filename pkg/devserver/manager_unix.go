@@ -0,0 +1,41 @@
+//go:build !windows
+
+package devserver
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// setProcessGroup starts cmd in its own process group, so a single signal to
+// -pgid reaches the dev server and every child process it spawns (webpack
+// workers, turbopack, etc), not just the npm/bun/pnpm wrapper.
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// terminateProcessGroup asks cmd's entire process group to shut down gracefully
+func terminateProcessGroup(cmd *exec.Cmd) error {
+	if cmd == nil || cmd.Process == nil {
+		return nil
+	}
+	return syscall.Kill(-cmd.Process.Pid, syscall.SIGTERM)
+}
+
+// killProcessGroup forcibly terminates cmd's entire process group
+func killProcessGroup(cmd *exec.Cmd) error {
+	if cmd == nil || cmd.Process == nil {
+		return nil
+	}
+	return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+}
+
+// terminatePID asks a single, independently-discovered process to shut down gracefully
+func terminatePID(pid int) error {
+	return syscall.Kill(pid, syscall.SIGTERM)
+}
+
+// killPID forcibly terminates a single, independently-discovered process
+func killPID(pid int) error {
+	return syscall.Kill(pid, syscall.SIGKILL)
+}
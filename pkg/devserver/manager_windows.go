@@ -0,0 +1,38 @@
+//go:build windows
+
+package devserver
+
+import (
+	"os/exec"
+	"strconv"
+)
+
+// setProcessGroup is a no-op on Windows; taskkill's /T flag handles tree-kill
+// instead of a process-group signal
+func setProcessGroup(cmd *exec.Cmd) {}
+
+// terminateProcessGroup asks cmd's process tree to shut down gracefully
+func terminateProcessGroup(cmd *exec.Cmd) error {
+	if cmd == nil || cmd.Process == nil {
+		return nil
+	}
+	return exec.Command("taskkill", "/T", "/PID", strconv.Itoa(cmd.Process.Pid)).Run()
+}
+
+// killProcessGroup forcibly terminates cmd's entire process tree
+func killProcessGroup(cmd *exec.Cmd) error {
+	if cmd == nil || cmd.Process == nil {
+		return nil
+	}
+	return exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(cmd.Process.Pid)).Run()
+}
+
+// terminatePID asks a single, independently-discovered process to shut down gracefully
+func terminatePID(pid int) error {
+	return exec.Command("taskkill", "/PID", strconv.Itoa(pid)).Run()
+}
+
+// killPID forcibly terminates a single, independently-discovered process
+func killPID(pid int) error {
+	return exec.Command("taskkill", "/F", "/PID", strconv.Itoa(pid)).Run()
+}
@@ -0,0 +1,75 @@
+package devserver
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// defaultRestartGlobs are the config-file globs that trigger a dev server
+// restart when no layrr.yaml override is present. Fast-reload tooling (Vite,
+// webpack, Next.js) handles everything else itself via HMR.
+var defaultRestartGlobs = []string{
+	"package.json",
+	"tsconfig.json",
+	"vite.config.*",
+	"next.config.*",
+	".env*",
+}
+
+// loadRestartGlobs reads the watch.restartOn list from <projectDir>/layrr.yaml,
+// falling back to defaultRestartGlobs if the file is absent or has no such key.
+func loadRestartGlobs(projectDir string) []string {
+	data, err := os.ReadFile(filepath.Join(projectDir, "layrr.yaml"))
+	if err != nil {
+		return defaultRestartGlobs
+	}
+
+	globs := parseRestartOn(data)
+	if len(globs) == 0 {
+		return defaultRestartGlobs
+	}
+	return globs
+}
+
+// parseRestartOn extracts a top-level "watch: / restartOn: [...]" list from
+// YAML text. This only understands that one narrow shape - a list of bare or
+// quoted strings nested two levels deep - rather than pulling in a
+// general-purpose YAML dependency for a single setting.
+func parseRestartOn(data []byte) []string {
+	var globs []string
+	inWatch := false
+	inRestartOn := false
+
+	scanner := bufio.NewScanner(strings.NewReader(string(data)))
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+
+		switch {
+		case indent == 0 && trimmed == "watch:":
+			inWatch = true
+			inRestartOn = false
+		case indent == 0:
+			inWatch = false
+			inRestartOn = false
+		case inWatch && trimmed == "restartOn:":
+			inRestartOn = true
+		case inRestartOn && strings.HasPrefix(trimmed, "- "):
+			item := strings.TrimSpace(strings.TrimPrefix(trimmed, "- "))
+			item = strings.Trim(item, `"'`)
+			if item != "" {
+				globs = append(globs, item)
+			}
+		case inRestartOn:
+			inRestartOn = false
+		}
+	}
+
+	return globs
+}
@@ -0,0 +1,23 @@
+package devserver
+
+// State describes where the supervised dev server process is in its lifecycle,
+// loosely modeled on supervisord's process state machine
+type State string
+
+const (
+	StateStopped   State = "stopped"
+	StateStarting  State = "starting"
+	StateRunning   State = "running"
+	StateBackoff   State = "backoff"
+	StateFatal     State = "fatal"
+	StateUnhealthy State = "unhealthy" // process is up but probeHealth's HEAD requests are failing
+)
+
+// Event is a state transition emitted as the dev server starts, crashes, restarts,
+// or is stopped. Callers subscribe via Manager.Subscribe().
+type Event struct {
+	State   State  `json:"state"`
+	Port    int    `json:"port,omitempty"`
+	Attempt int    `json:"attempt,omitempty"`
+	Message string `json:"message,omitempty"`
+}
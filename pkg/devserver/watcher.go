@@ -0,0 +1,71 @@
+package devserver
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/thetronjohnson/layrr/pkg/watcher"
+)
+
+// Watcher observes a project's config files and hot-restarts the managed dev
+// server when one changes. Source edits are left to the dev server's own HMR;
+// only changes matching restartGlobs (package.json, tsconfig.json,
+// vite.config.*, next.config.*, .env*, overridable via layrr.yaml) warrant a
+// full restart.
+type Watcher struct {
+	fileWatcher *watcher.Watcher
+	manager     *Manager
+}
+
+// NewWatcher starts watching projectDir for config changes that should
+// restart manager's dev server. State transitions are emitted on the same
+// channel as manager.Subscribe(), so the sidebar needs no separate subscription
+// to show a "Restarting..." indicator.
+func NewWatcher(projectDir string, manager *Manager) (*Watcher, error) {
+	restartGlobs := loadRestartGlobs(projectDir)
+
+	fileWatcher, err := watcher.NewWatcher(projectDir, watcher.WatchOptions{
+		MatchFile: func(relPath string) bool {
+			return matchesAnyGlob(filepath.Base(relPath), restartGlobs)
+		},
+	}, false, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to watch project config: %w", err)
+	}
+
+	w := &Watcher{fileWatcher: fileWatcher, manager: manager}
+	fileWatcher.OnChange(w.handleChange)
+
+	return w, nil
+}
+
+// handleChange restarts the dev server in response to a config change
+func (w *Watcher) handleChange(files []string) {
+	w.manager.setState(StateBackoff, 0, fmt.Sprintf("restarting dev server: config changed (%s)", strings.Join(files, ", ")))
+
+	if err := w.manager.Stop(); err != nil {
+		w.manager.setState(StateFatal, 0, fmt.Sprintf("failed to stop dev server for restart: %v", err))
+		return
+	}
+
+	if err := w.manager.Start(); err != nil {
+		w.manager.setState(StateFatal, 0, fmt.Sprintf("failed to restart dev server: %v", err))
+	}
+}
+
+// Close stops watching project config files
+func (w *Watcher) Close() error {
+	return w.fileWatcher.Close()
+}
+
+// matchesAnyGlob reports whether name matches any of the glob patterns, using
+// filepath.Match semantics (e.g. "vite.config.*")
+func matchesAnyGlob(name string, globs []string) bool {
+	for _, g := range globs {
+		if ok, err := filepath.Match(g, name); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
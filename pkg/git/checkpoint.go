@@ -0,0 +1,376 @@
+package git
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// checkpointRefPrefix namespaces checkpoint commits away from the user's real
+// branches so `git branch` and friends never show them
+const checkpointRefPrefix = "refs/layrr/checkpoints/"
+
+// Checkpoint records one Claude Code turn's effect on the working tree: the
+// prompt that produced it, the files it touched, and the commit objects that
+// captured the working tree just before and just after the turn ran (both
+// created with `git stash create`, which never touches the index or the
+// user's branch). PostTurnHash is empty until CompleteCheckpoint records it,
+// which happens once the turn's events finish.
+type Checkpoint struct {
+	ID           string    `json:"id"` // turn id
+	Prompt       string    `json:"prompt"`
+	Files        []string  `json:"files"`
+	CommitHash   string    `json:"commitHash"`   // working tree snapshot just before the turn ran
+	ParentHash   string    `json:"parentHash"`   // real git HEAD when the session started tracking checkpoints
+	PostTurnHash string    `json:"postTurnHash"` // working tree snapshot just after the turn finished
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+// checkpointsDir returns (and ensures) the directory holding checkpoint sidecar
+// metadata, kept inside .git so it never appears in the user's working tree
+func (g *GitManager) checkpointsDir() (string, error) {
+	dir := filepath.Join(g.projectDir, ".git", "layrr-checkpoints")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create checkpoints directory: %w", err)
+	}
+	return dir, nil
+}
+
+// CreateCheckpoint snapshots the current working tree onto the hidden
+// layrr/checkpoints ref before a Claude Code turn runs, tagging it with the
+// prompt text, turn id, and the files Claude is about to touch.
+func (g *GitManager) CreateCheckpoint(turnID, prompt string, touchedFiles []string) (*Checkpoint, error) {
+	parentHash, err := g.GetCurrentCommitHash()
+	if err != nil {
+		return nil, err
+	}
+
+	// git stash create captures the working tree + index as a commit object
+	// without modifying either, so the user's branch is never touched
+	stashHash, err := g.runGit("stash", "create")
+	if err != nil {
+		return nil, fmt.Errorf("failed to snapshot working tree: %w", err)
+	}
+
+	if stashHash == "" {
+		// No local changes to capture; point the checkpoint at HEAD itself
+		stashHash = parentHash
+	}
+
+	refName := checkpointRefPrefix + turnID
+	if _, err := g.runGit("update-ref", refName, stashHash); err != nil {
+		return nil, fmt.Errorf("failed to record checkpoint ref: %w", err)
+	}
+
+	checkpoint := &Checkpoint{
+		ID:         turnID,
+		Prompt:     prompt,
+		Files:      touchedFiles,
+		CommitHash: stashHash,
+		ParentHash: parentHash,
+		CreatedAt:  time.Now(),
+	}
+
+	if err := g.saveCheckpointSidecar(checkpoint); err != nil {
+		return nil, err
+	}
+
+	return checkpoint, nil
+}
+
+func (g *GitManager) saveCheckpointSidecar(c *Checkpoint) error {
+	dir, err := g.checkpointsDir()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode checkpoint metadata: %w", err)
+	}
+
+	path := filepath.Join(dir, c.ID+".json")
+	return os.WriteFile(path, data, 0644)
+}
+
+// UpdateCheckpointFiles overwrites a checkpoint's recorded file list. It
+// exists because CreateCheckpoint runs before a turn starts, when the files
+// it's about to touch aren't known yet; a caller that derives the real list
+// afterward (e.g. from the turn's Edit/Write tool calls) fills it in here.
+func (g *GitManager) UpdateCheckpointFiles(id string, files []string) error {
+	checkpoint, err := g.GetCheckpoint(id)
+	if err != nil {
+		return err
+	}
+	checkpoint.Files = files
+	return g.saveCheckpointSidecar(checkpoint)
+}
+
+// CompleteCheckpoint snapshots the working tree as it stands once a turn has
+// finished, recording it as the checkpoint's PostTurnHash. Without this,
+// RevertCheckpoint and GetCheckpointDiff would only have CommitHash (the
+// snapshot from just *before* the turn ran) to work with, and so could only
+// diff/revert whatever earlier turns changed, not the turn itself.
+func (g *GitManager) CompleteCheckpoint(id string) error {
+	checkpoint, err := g.GetCheckpoint(id)
+	if err != nil {
+		return err
+	}
+
+	stashHash, err := g.runGit("stash", "create")
+	if err != nil {
+		return fmt.Errorf("failed to snapshot working tree: %w", err)
+	}
+	if stashHash == "" {
+		// No uncommitted changes left; the working tree now matches HEAD
+		stashHash, err = g.GetCurrentCommitHash()
+		if err != nil {
+			return err
+		}
+	}
+
+	checkpoint.PostTurnHash = stashHash
+	return g.saveCheckpointSidecar(checkpoint)
+}
+
+// GetCheckpoint loads the sidecar metadata for a checkpoint by turn id
+func (g *GitManager) GetCheckpoint(id string) (*Checkpoint, error) {
+	dir, err := g.checkpointsDir()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, id+".json"))
+	if err != nil {
+		return nil, fmt.Errorf("checkpoint %s not found: %w", id, err)
+	}
+
+	var c Checkpoint
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("failed to parse checkpoint metadata: %w", err)
+	}
+
+	return &c, nil
+}
+
+// ListCheckpoints returns all recorded checkpoints, oldest first
+func (g *GitManager) ListCheckpoints() ([]*Checkpoint, error) {
+	dir, err := g.checkpointsDir()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list checkpoints: %w", err)
+	}
+
+	var checkpoints []*Checkpoint
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		id := strings.TrimSuffix(entry.Name(), ".json")
+		c, err := g.GetCheckpoint(id)
+		if err != nil {
+			continue
+		}
+		checkpoints = append(checkpoints, c)
+	}
+
+	sort.Slice(checkpoints, func(i, j int) bool {
+		return checkpoints[i].CreatedAt.Before(checkpoints[j].CreatedAt)
+	})
+
+	return checkpoints, nil
+}
+
+// GetCheckpointDiff returns the per-file unified diff between the working
+// tree just before the turn ran (CommitHash) and just after it finished
+// (PostTurnHash), restricted to the files it touched. It returns an empty
+// diff for a checkpoint whose turn hasn't finished yet (PostTurnHash not yet
+// recorded by CompleteCheckpoint).
+func (g *GitManager) GetCheckpointDiff(id string) (map[string]string, error) {
+	checkpoint, err := g.GetCheckpoint(id)
+	if err != nil {
+		return nil, err
+	}
+
+	postTurnHash := checkpoint.PostTurnHash
+	if postTurnHash == "" {
+		postTurnHash = checkpoint.CommitHash
+	}
+
+	diffs := make(map[string]string)
+	for _, file := range checkpoint.Files {
+		diff, err := g.runGit("diff", checkpoint.CommitHash, postTurnHash, "--", file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to diff %s: %w", file, err)
+		}
+		diffs[file] = diff
+	}
+
+	return diffs, nil
+}
+
+// RevertConflictError reports that reverting a checkpoint couldn't cleanly
+// three-way merge one or more files: each listed file's working tree copy
+// was left exactly as it was, for the caller to resolve by hand (or discard
+// the revert) instead of losing whatever it held.
+type RevertConflictError struct {
+	Files []string
+}
+
+func (e *RevertConflictError) Error() string {
+	return fmt.Sprintf("revert has conflicts in: %s", strings.Join(e.Files, ", "))
+}
+
+// RevertCheckpoint restores just the files a turn modified back to their state
+// before that turn, leaving unrelated edits (the user's own, or later turns')
+// intact by three-way merging each file's current working tree content
+// against the checkpoint's before/after snapshots, rather than overwriting it
+// outright. Files that don't merge cleanly are left untouched and reported
+// via a *RevertConflictError.
+func (g *GitManager) RevertCheckpoint(id string) error {
+	checkpoint, err := g.GetCheckpoint(id)
+	if err != nil {
+		return err
+	}
+
+	postTurnHash := checkpoint.PostTurnHash
+	if postTurnHash == "" {
+		postTurnHash = checkpoint.CommitHash
+	}
+
+	var conflicted []string
+	for _, file := range checkpoint.Files {
+		clean, err := g.threeWayRevertFile(file, postTurnHash, checkpoint.CommitHash)
+		if err != nil {
+			return fmt.Errorf("failed to revert %s: %w", file, err)
+		}
+		if !clean {
+			conflicted = append(conflicted, file)
+		}
+	}
+
+	if len(conflicted) > 0 {
+		return &RevertConflictError{Files: conflicted}
+	}
+	return nil
+}
+
+// threeWayRevertFile merges file's current working tree content (which may
+// carry edits made after the checkpoint, by the user or a later turn)
+// against base (the turn's post-turn snapshot) and target (the turn's
+// pre-turn snapshot), via `git merge-file`. That applies the turn's changes
+// in reverse onto the current content instead of discarding it outright. It
+// reports false, leaving the working tree file exactly as it was, if the
+// merge produced conflicts.
+func (g *GitManager) threeWayRevertFile(file, baseHash, targetHash string) (bool, error) {
+	absPath := filepath.Join(g.projectDir, file)
+	ours, err := os.ReadFile(absPath)
+	if err != nil {
+		return false, fmt.Errorf("failed to read working tree copy: %w", err)
+	}
+
+	base, err := g.readBlob(baseHash, file)
+	if err != nil {
+		return false, err
+	}
+	target, err := g.readBlob(targetHash, file)
+	if err != nil {
+		return false, err
+	}
+
+	tmpDir, err := os.MkdirTemp("", "layrr-revert-*")
+	if err != nil {
+		return false, fmt.Errorf("failed to create merge workspace: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	oursPath := filepath.Join(tmpDir, "ours")
+	basePath := filepath.Join(tmpDir, "base")
+	targetPath := filepath.Join(tmpDir, "target")
+	if err := os.WriteFile(oursPath, ours, 0644); err != nil {
+		return false, fmt.Errorf("failed to stage merge workspace: %w", err)
+	}
+	if err := os.WriteFile(basePath, base, 0644); err != nil {
+		return false, fmt.Errorf("failed to stage merge workspace: %w", err)
+	}
+	if err := os.WriteFile(targetPath, target, 0644); err != nil {
+		return false, fmt.Errorf("failed to stage merge workspace: %w", err)
+	}
+
+	// `git merge-file ours base target` rewrites ours in place with the
+	// three-way merge result; its exit code is the conflict count (0 = clean),
+	// not a normal success/failure code, so it's run directly rather than
+	// through runGit.
+	cmd := exec.Command("git", "merge-file", oursPath, basePath, targetPath)
+	runErr := cmd.Run()
+
+	merged, err := os.ReadFile(oursPath)
+	if err != nil {
+		return false, fmt.Errorf("failed to read merge result: %w", err)
+	}
+
+	if exitErr, ok := runErr.(*exec.ExitError); ok {
+		if exitErr.ExitCode() > 0 {
+			return false, nil // conflicts; working tree file is untouched
+		}
+		return false, fmt.Errorf("git merge-file failed: %w", exitErr)
+	} else if runErr != nil {
+		return false, fmt.Errorf("git merge-file failed: %w", runErr)
+	}
+
+	if err := os.WriteFile(absPath, merged, 0644); err != nil {
+		return false, fmt.Errorf("failed to write merged result: %w", err)
+	}
+	return true, nil
+}
+
+// readBlob returns a file's raw content as it existed at commitHash, without
+// the trailing-whitespace trimming runGit applies (which would corrupt file
+// content used in a merge)
+func (g *GitManager) readBlob(commitHash, file string) ([]byte, error) {
+	cmd := exec.Command("git", "show", commitHash+":"+file)
+	cmd.Dir = g.projectDir
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s at %s: %w", file, shortHash(commitHash), err)
+	}
+	return out, nil
+}
+
+// SquashCheckpointsInto collapses every recorded checkpoint into a single real
+// commit on the current branch with the given message, then clears the
+// checkpoint history now that it's been folded into normal git history.
+func (g *GitManager) SquashCheckpointsInto(message string) error {
+	checkpoints, err := g.ListCheckpoints()
+	if err != nil {
+		return err
+	}
+	if len(checkpoints) == 0 {
+		return fmt.Errorf("no checkpoints to squash")
+	}
+
+	if err := g.CreateCommit(message); err != nil {
+		return err
+	}
+
+	dir, err := g.checkpointsDir()
+	if err != nil {
+		return err
+	}
+
+	for _, c := range checkpoints {
+		os.Remove(filepath.Join(dir, c.ID+".json"))
+		g.runGit("update-ref", "-d", checkpointRefPrefix+c.ID)
+	}
+
+	return nil
+}
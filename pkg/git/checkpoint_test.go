@@ -0,0 +1,125 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// newTestRepo creates a throwaway git repository with an initial commit of
+// file containing initial, and returns a GitManager rooted there
+func newTestRepo(t *testing.T, file, initial string) *GitManager {
+	t.Helper()
+
+	dir := t.TempDir()
+	runOrFail(t, dir, "init")
+	runOrFail(t, dir, "config", "user.email", "test@example.com")
+	runOrFail(t, dir, "config", "user.name", "Test")
+
+	if err := os.WriteFile(filepath.Join(dir, file), []byte(initial), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", file, err)
+	}
+	runOrFail(t, dir, "add", "-A")
+	runOrFail(t, dir, "commit", "-m", "initial")
+
+	return NewGitManager(dir)
+}
+
+func runOrFail(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v failed: %v (%s)", args, err, out)
+	}
+}
+
+func TestRevertCheckpointPreservesLaterEdits(t *testing.T) {
+	const file = "app.txt"
+	initial := "top\nmiddle1\nmiddle2\nmiddle3\nmiddle4\nmiddle5\nbottom\n"
+	g := newTestRepo(t, file, initial)
+	path := filepath.Join(g.projectDir, file)
+
+	if _, err := g.CreateCheckpoint("turn-1", "edit the bottom", nil); err != nil {
+		t.Fatalf("CreateCheckpoint: %v", err)
+	}
+
+	// Simulate the turn's edit (near the bottom of the file), then record the
+	// files it touched and the post-turn snapshot the same way
+	// CheckpointingBackend does once the turn completes
+	turnEdit := "top\nmiddle1\nmiddle2\nmiddle3\nmiddle4\nmiddle5\nbottom EDITED BY TURN\n"
+	if err := os.WriteFile(path, []byte(turnEdit), 0644); err != nil {
+		t.Fatalf("writing turn edit: %v", err)
+	}
+	if err := g.UpdateCheckpointFiles("turn-1", []string{file}); err != nil {
+		t.Fatalf("UpdateCheckpointFiles: %v", err)
+	}
+	if err := g.CompleteCheckpoint("turn-1"); err != nil {
+		t.Fatalf("CompleteCheckpoint: %v", err)
+	}
+
+	// Simulate an unrelated user edit made after the turn, far from the line
+	// the turn touched
+	userEdit := "top EDITED BY USER\nmiddle1\nmiddle2\nmiddle3\nmiddle4\nmiddle5\nbottom EDITED BY TURN\n"
+	if err := os.WriteFile(path, []byte(userEdit), 0644); err != nil {
+		t.Fatalf("writing user edit: %v", err)
+	}
+
+	if err := g.RevertCheckpoint("turn-1"); err != nil {
+		t.Fatalf("RevertCheckpoint: %v", err)
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading reverted file: %v", err)
+	}
+
+	want := "top EDITED BY USER\nmiddle1\nmiddle2\nmiddle3\nmiddle4\nmiddle5\nbottom\n"
+	if string(got) != want {
+		t.Fatalf("after revert, got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRevertCheckpointReportsConflicts(t *testing.T) {
+	const file = "app.txt"
+	g := newTestRepo(t, file, "line1\nline2\nline3\n")
+	path := filepath.Join(g.projectDir, file)
+
+	if _, err := g.CreateCheckpoint("turn-1", "add a line", nil); err != nil {
+		t.Fatalf("CreateCheckpoint: %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte("line1\nline2 EDITED BY TURN\nline3\n"), 0644); err != nil {
+		t.Fatalf("writing turn edit: %v", err)
+	}
+	if err := g.UpdateCheckpointFiles("turn-1", []string{file}); err != nil {
+		t.Fatalf("UpdateCheckpointFiles: %v", err)
+	}
+	if err := g.CompleteCheckpoint("turn-1"); err != nil {
+		t.Fatalf("CompleteCheckpoint: %v", err)
+	}
+
+	// A conflicting edit to the exact same line the turn changed
+	conflicting := []byte("line1\nline2 EDITED BY USER\nline3\n")
+	if err := os.WriteFile(path, conflicting, 0644); err != nil {
+		t.Fatalf("writing conflicting edit: %v", err)
+	}
+
+	revertErr := g.RevertCheckpoint("turn-1")
+	conflictErr, ok := revertErr.(*RevertConflictError)
+	if !ok {
+		t.Fatalf("expected *RevertConflictError, got %v", revertErr)
+	}
+	if len(conflictErr.Files) != 1 || conflictErr.Files[0] != file {
+		t.Fatalf("expected conflict on %s, got %v", file, conflictErr.Files)
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading file after conflicting revert: %v", err)
+	}
+	if string(got) != string(conflicting) {
+		t.Fatalf("conflicting file was modified, want it left untouched:\ngot:\n%s\nwant:\n%s", got, conflicting)
+	}
+}
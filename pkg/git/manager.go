@@ -0,0 +1,154 @@
+package git
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// GitManager wraps the `git` CLI for a single project directory
+type GitManager struct {
+	projectDir string
+}
+
+// Commit represents a single entry in the project's commit history
+type Commit struct {
+	Hash    string    `json:"hash"`
+	Message string    `json:"message"`
+	Author  string    `json:"author"`
+	Date    time.Time `json:"date"`
+}
+
+// NewGitManager creates a git manager for the given project directory
+func NewGitManager(projectDir string) *GitManager {
+	return &GitManager{projectDir: projectDir}
+}
+
+// IsGitRepo reports whether the project directory is inside a git repository
+func (g *GitManager) IsGitRepo() bool {
+	cmd := exec.Command("git", "rev-parse", "--is-inside-work-tree")
+	cmd.Dir = g.projectDir
+	return cmd.Run() == nil
+}
+
+// CreateCommit stages all changes and creates a commit with the given message
+func (g *GitManager) CreateCommit(message string) error {
+	addCmd := exec.Command("git", "add", "-A")
+	addCmd.Dir = g.projectDir
+	if output, err := addCmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to stage changes: %w (%s)", err, strings.TrimSpace(string(output)))
+	}
+
+	commitCmd := exec.Command("git", "commit", "-m", message)
+	commitCmd.Dir = g.projectDir
+	if output, err := commitCmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to create commit: %w (%s)", err, strings.TrimSpace(string(output)))
+	}
+
+	return nil
+}
+
+// GetCommitHistory returns the most recent commits, newest first
+func (g *GitManager) GetCommitHistory(limit int) ([]Commit, error) {
+	format := "%H%x1f%s%x1f%an%x1f%aI%x1e"
+	cmd := exec.Command("git", "log", fmt.Sprintf("-n%d", limit), "--pretty=format:"+format)
+	cmd.Dir = g.projectDir
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read commit history: %w", err)
+	}
+
+	var commits []Commit
+	for _, entry := range strings.Split(string(output), "\x1e") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		fields := strings.Split(entry, "\x1f")
+		if len(fields) != 4 {
+			continue
+		}
+
+		date, err := time.Parse(time.RFC3339, fields[3])
+		if err != nil {
+			date = time.Time{}
+		}
+
+		commits = append(commits, Commit{
+			Hash:    fields[0],
+			Message: fields[1],
+			Author:  fields[2],
+			Date:    date,
+		})
+	}
+
+	return commits, nil
+}
+
+// CheckoutCommit checks out a specific commit hash
+func (g *GitManager) CheckoutCommit(commitHash string) error {
+	cmd := exec.Command("git", "checkout", commitHash)
+	cmd.Dir = g.projectDir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to checkout commit %s: %w (%s)", commitHash, err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// GetCurrentCommitHash returns the hash of HEAD
+func (g *GitManager) GetCurrentCommitHash() (string, error) {
+	cmd := exec.Command("git", "rev-parse", "HEAD")
+	cmd.Dir = g.projectDir
+
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get current commit hash: %w", err)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// currentBranch returns the name of the currently checked out branch
+func (g *GitManager) currentBranch() (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD")
+	cmd.Dir = g.projectDir
+
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get current branch: %w", err)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// refExists reports whether the given ref (branch, tag, or commit) exists
+func (g *GitManager) refExists(ref string) bool {
+	cmd := exec.Command("git", "rev-parse", "--verify", ref)
+	cmd.Dir = g.projectDir
+	return cmd.Run() == nil
+}
+
+// runGit is a small helper for checkpoint commands that don't fit the
+// higher-level helpers above
+func (g *GitManager) runGit(args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = g.projectDir
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("git %s failed: %w (%s)", strings.Join(args, " "), err, strings.TrimSpace(string(output)))
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// shortHash truncates a commit hash to the short form git log --oneline uses
+func shortHash(hash string) string {
+	if len(hash) < 7 {
+		return hash
+	}
+	return hash[:7]
+}
@@ -1,54 +1,164 @@
 package proxy
 
 import (
+	"bytes"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"image"
 	"os"
 	"path/filepath"
+	"regexp"
 	"sort"
 	"strings"
 	"time"
+
+	"github.com/thetronjohnson/layrr/pkg/analyzer"
 )
 
-// SaveImageToProject saves a base64-encoded image to the project's public/images/ directory
-// and returns the relative path for use in Next.js Image components
-func SaveImageToProject(imageBase64, imageType, projectDir string) (string, error) {
-	// 1. Ensure public/images/ directory exists
-	imagesDir := filepath.Join(projectDir, "public", "images")
+// SaveImageOptions carries the optional metadata SaveImageToProject records
+// in an asset's sidecar file. The zero value saves a plain image with no
+// alt text or known source.
+type SaveImageOptions struct {
+	Slug   string // used to build the filename; defaults to "image"
+	Alt    string // accessible alt text, if the caller has one
+	Source string // the prompt or URL this image came from, if any
+}
+
+// imageSidecar is the <basename>.json metadata file written alongside every
+// saved image, PhotoPrism-style, so ListImagesInPublic doesn't have to
+// re-decode every image just to report its dimensions
+type imageSidecar struct {
+	MIME      string    `json:"mime"`
+	Size      int64     `json:"size"`
+	Width     int       `json:"width"`
+	Height    int       `json:"height"`
+	Alt       string    `json:"alt,omitempty"`
+	Source    string    `json:"source,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// SaveImageToProject saves a base64-encoded image to the <publicDir>/images/
+// directory under a content-addressed filename, "<hash>-<slug><ext>" where
+// hash is the first 16 hex characters of the decoded bytes' SHA-256, so
+// re-saving the same bytes under the same slug reuses the existing file
+// rather than writing a duplicate (the sidecar is rewritten each time). It
+// writes a <basename>.json sidecar next to the image recording its decoded
+// MIME type, size, intrinsic dimensions, and opts' alt/source, then, for
+// formats the image package can decode, generates a responsive derivative
+// set via ImageProcessor. The returned ProcessedImage's OriginalPath is
+// always populated; Variants and SrcSet are empty if the format (e.g. SVG)
+// isn't one GenerateResponsiveSet supports.
+func SaveImageToProject(imageBase64, imageType, projectDir, publicDir string, opts SaveImageOptions) (ProcessedImage, error) {
+	imagesDir := filepath.Join(publicDir, "images")
 	if err := ensurePublicImagesDir(imagesDir); err != nil {
-		return "", err
+		return ProcessedImage{}, err
 	}
 
-	// 2. Generate unique filename with appropriate extension
-	ext := getImageExtension(imageType)
-	filename := generateUniqueFilename(ext)
-
-	// 3. Decode base64 image data
 	imageData, err := base64.StdEncoding.DecodeString(imageBase64)
 	if err != nil {
-		return "", fmt.Errorf("failed to decode image data: %w", err)
+		return ProcessedImage{}, fmt.Errorf("failed to decode image data: %w", err)
 	}
 
-	// 4. Validate image size (max 5MB for actual assets)
 	const maxSize = 5 * 1024 * 1024 // 5MB
 	if len(imageData) > maxSize {
-		return "", fmt.Errorf("image size (%d bytes) exceeds maximum allowed size (5MB)", len(imageData))
+		return ProcessedImage{}, fmt.Errorf("image size (%d bytes) exceeds maximum allowed size (5MB)", len(imageData))
 	}
 
-	// 5. Write image file
+	ext := getImageExtension(imageType)
+	filename := fmt.Sprintf("%s-%s%s", contentHash(imageData), slugify(opts.Slug), ext)
 	filePath := filepath.Join(imagesDir, filename)
-	if err := os.WriteFile(filePath, imageData, 0644); err != nil {
-		return "", fmt.Errorf("failed to write image file: %w", err)
+	relativePath := "/images/" + filename
+
+	if _, err := os.Stat(filePath); err == nil {
+		fmt.Printf("[ImageHandler] ♻️  Image already exists, reusing: %s\n", filePath)
+	} else {
+		if err := os.WriteFile(filePath, imageData, 0644); err != nil {
+			return ProcessedImage{}, fmt.Errorf("failed to write image file: %w", err)
+		}
+		fmt.Printf("[ImageHandler] ✅ Image saved successfully:\n")
+		fmt.Printf("  File: %s\n", filePath)
+		fmt.Printf("  Path for code: %s\n", relativePath)
 	}
 
-	// 6. Return relative path for Next.js (e.g., "/images/1732241234-image.jpg")
-	relativePath := "/images/" + filename
+	if err := writeImageSidecar(filePath, imageType, imageData, opts); err != nil {
+		fmt.Printf("[ImageHandler] ⚠️  Failed to write sidecar metadata for %s: %v\n", filePath, err)
+	}
+
+	// Generate responsive derivatives; a format GenerateResponsiveSet can't
+	// decode (SVG, most GIFs) is reported as just the original, not an error
+	processor := NewImageProcessor(projectDir, publicDir, relativePath)
+	result, err := processor.GenerateResponsiveSet()
+	if err != nil {
+		fmt.Printf("[ImageHandler] ⚠️  Skipping responsive derivatives for %s: %v\n", relativePath, err)
+		return ProcessedImage{OriginalPath: relativePath}, nil
+	}
+
+	return result, nil
+}
+
+// writeImageSidecar decodes just enough of imageData to record its intrinsic
+// dimensions, then writes the <basename>.json metadata file next to imagePath
+func writeImageSidecar(imagePath, mimeType string, imageData []byte, opts SaveImageOptions) error {
+	width, height := 0, 0
+	if cfg, _, err := image.DecodeConfig(bytes.NewReader(imageData)); err == nil {
+		width, height = cfg.Width, cfg.Height
+	}
+
+	sidecar := imageSidecar{
+		MIME:      strings.ToLower(strings.TrimSpace(mimeType)),
+		Size:      int64(len(imageData)),
+		Width:     width,
+		Height:    height,
+		Alt:       opts.Alt,
+		Source:    opts.Source,
+		CreatedAt: time.Now(),
+	}
+
+	data, err := json.MarshalIndent(sidecar, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(sidecarPath(imagePath), data, 0644)
+}
+
+// sidecarPath returns the <basename>.json path for an image file
+func sidecarPath(imagePath string) string {
+	return strings.TrimSuffix(imagePath, filepath.Ext(imagePath)) + ".json"
+}
+
+// contentHash returns the first 16 hex characters of data's SHA-256, used as
+// the content-addressable portion of a saved image's filename
+func contentHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+var (
+	slugSanitizer    = regexp.MustCompile(`[^a-z0-9]+`)
+	hashPrefixFormat = regexp.MustCompile(`^[0-9a-f]{16}-`)
+)
 
-	fmt.Printf("[ImageHandler] ✅ Image saved successfully:\n")
-	fmt.Printf("  File: %s\n", filePath)
-	fmt.Printf("  Path for code: %s\n", relativePath)
+// slugify normalizes s into a filename-safe slug, defaulting to "image" when
+// s is empty or sanitizes away to nothing
+func slugify(s string) string {
+	s = slugSanitizer.ReplaceAllString(strings.ToLower(strings.TrimSpace(s)), "-")
+	s = strings.Trim(s, "-")
+	if s == "" {
+		return "image"
+	}
+	return s
+}
 
-	return relativePath, nil
+// hashFromFilename extracts the leading <hash>- prefix SaveImageToProject
+// names every file with, or "" for a file saved before this scheme existed
+func hashFromFilename(name string) string {
+	if !hashPrefixFormat.MatchString(name) {
+		return ""
+	}
+	return name[:strings.IndexByte(name, '-')]
 }
 
 // ensurePublicImagesDir creates the public/images/ directory if it doesn't exist
@@ -59,12 +169,6 @@ func ensurePublicImagesDir(imagesDir string) error {
 	return nil
 }
 
-// generateUniqueFilename creates a unique filename using timestamp
-func generateUniqueFilename(ext string) string {
-	timestamp := time.Now().Unix()
-	return fmt.Sprintf("%d-image%s", timestamp, ext)
-}
-
 // getImageExtension returns the appropriate file extension for the given MIME type
 func getImageExtension(mimeType string) string {
 	// Normalize MIME type
@@ -108,17 +212,23 @@ func ValidateImageType(mimeType string) bool {
 	return false
 }
 
-// ImageInfo represents metadata about an image file
+// ImageInfo represents metadata about an image file, merging its sidecar
+// (if one exists) with filesystem stats
 type ImageInfo struct {
 	Path    string    `json:"path"`    // Relative path like "/images/hero.jpg"
 	Name    string    `json:"name"`    // Just the filename like "hero.jpg"
 	Size    int64     `json:"size"`    // File size in bytes
 	ModTime time.Time `json:"modTime"` // Modification time for sorting
+	Width   int       `json:"width,omitempty"`
+	Height  int       `json:"height,omitempty"`
+	Alt     string    `json:"alt,omitempty"`
+	Hash    string    `json:"hash,omitempty"`
+	Source  string    `json:"source,omitempty"`
 }
 
-// ListImagesInPublic scans the public directory recursively and returns all image files
-func ListImagesInPublic(projectDir string) ([]ImageInfo, error) {
-	publicDir := filepath.Join(projectDir, "public")
+// ListImagesInPublic scans publicDir recursively and returns all image
+// files, merging each one's sidecar metadata (if present) into its ImageInfo
+func ListImagesInPublic(publicDir string) ([]ImageInfo, error) {
 	var images []ImageInfo
 
 	fmt.Printf("[ImageHandler] Scanning directory: %s\n", publicDir)
@@ -144,24 +254,35 @@ func ListImagesInPublic(projectDir string) ([]ImageInfo, error) {
 
 		// Check if file is an image based on extension
 		ext := strings.ToLower(filepath.Ext(path))
-		if ext == ".jpg" || ext == ".jpeg" || ext == ".png" || ext == ".webp" || ext == ".gif" || ext == ".svg" {
-			// Get relative path from public directory
-			relPath, err := filepath.Rel(publicDir, path)
-			if err != nil {
-				return err
-			}
-
-			// Convert to web path (forward slashes, prepend /)
-			webPath := "/" + filepath.ToSlash(relPath)
-
-			images = append(images, ImageInfo{
-				Path:    webPath,
-				Name:    info.Name(),
-				Size:    info.Size(),
-				ModTime: info.ModTime(),
-			})
+		if ext != ".jpg" && ext != ".jpeg" && ext != ".png" && ext != ".webp" && ext != ".gif" && ext != ".svg" {
+			return nil // also skips sidecar .json files
+		}
+
+		// Get relative path from public directory
+		relPath, err := filepath.Rel(publicDir, path)
+		if err != nil {
+			return err
+		}
+
+		// Convert to web path (forward slashes, prepend /)
+		webPath := "/" + filepath.ToSlash(relPath)
+
+		imgInfo := ImageInfo{
+			Path:    webPath,
+			Name:    info.Name(),
+			Size:    info.Size(),
+			ModTime: info.ModTime(),
+			Hash:    hashFromFilename(info.Name()),
+		}
+
+		if sidecar, err := readImageSidecar(path); err == nil {
+			imgInfo.Width = sidecar.Width
+			imgInfo.Height = sidecar.Height
+			imgInfo.Alt = sidecar.Alt
+			imgInfo.Source = sidecar.Source
 		}
 
+		images = append(images, imgInfo)
 		return nil
 	})
 
@@ -176,3 +297,40 @@ func ListImagesInPublic(projectDir string) ([]ImageInfo, error) {
 
 	return images, nil
 }
+
+// readImageSidecar loads the <basename>.json metadata file for imagePath, if one exists
+func readImageSidecar(imagePath string) (imageSidecar, error) {
+	data, err := os.ReadFile(sidecarPath(imagePath))
+	if err != nil {
+		return imageSidecar{}, err
+	}
+
+	var sidecar imageSidecar
+	if err := json.Unmarshal(data, &sidecar); err != nil {
+		return imageSidecar{}, err
+	}
+	return sidecar, nil
+}
+
+// LookupBySource reports whether an image sourced from url has already been
+// saved into projectDir's public asset directory, so a caller can skip
+// re-fetching it. It returns the existing image's path and true on a hit.
+func LookupBySource(projectDir, url string) (string, bool, error) {
+	ctx, err := analyzer.AnalyzeProject(projectDir)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to analyze project: %w", err)
+	}
+
+	publicDir := ctx.Adapter().PublicDir(projectDir)
+	images, err := ListImagesInPublic(publicDir)
+	if err != nil {
+		return "", false, err
+	}
+
+	for _, img := range images {
+		if img.Source == url {
+			return img.Path, true, nil
+		}
+	}
+	return "", false, nil
+}
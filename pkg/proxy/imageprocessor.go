@@ -0,0 +1,329 @@
+package proxy
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// layrrDirName is the per-project directory (alongside .git) that holds
+// this run's session token (see config.GetSessionTokenPath) and, as of this
+// package, its processed-image cache
+const layrrDirName = ".layrr"
+
+// responsiveWidths are the srcSet breakpoints GenerateResponsiveSet builds a
+// variant for, mirroring the widths Next.js's own image optimizer defaults to
+var responsiveWidths = []int{320, 640, 1024, 1920}
+
+// ImageVariant is one generated derivative of a source image
+type ImageVariant struct {
+	Path   string `json:"path"` // relative to the project's public dir, e.g. "/images/cache/.../resize_640x0.jpg"
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+// ProcessedImage is what an ImageProcessor operation returns: the generated
+// variant(s) plus everything a Next.js <Image> component (or any other
+// framework's equivalent) needs to reference them
+type ProcessedImage struct {
+	OriginalPath string         `json:"originalPath"`
+	Variants     []ImageVariant `json:"variants"`
+	SrcSet       string         `json:"srcSet"`
+	Width        int            `json:"width"` // intrinsic size of the primary (largest) variant
+	Height       int            `json:"height"`
+}
+
+// ImageProcessor generates resized derivatives of a single source image
+// under a project and caches them on disk, keyed by a hash of the source
+// bytes and the requested operation so identical requests after the first
+// are a cache read instead of a re-encode.
+type ImageProcessor struct {
+	projectDir string
+	publicDir  string // absolute path to the framework's public asset directory
+	sourcePath string // absolute path to the source image, under publicDir
+}
+
+// NewImageProcessor returns a processor for the image at publicPath (e.g.
+// "/images/hero.jpg", as returned by SaveImageToProject), resolved against
+// publicDir
+func NewImageProcessor(projectDir, publicDir, publicPath string) *ImageProcessor {
+	return &ImageProcessor{
+		projectDir: projectDir,
+		publicDir:  publicDir,
+		sourcePath: filepath.Join(publicDir, filepath.FromSlash(strings.TrimPrefix(publicPath, "/"))),
+	}
+}
+
+// Resize scales the source to fit within width x height, preserving aspect
+// ratio (so the result is never larger than the box, but may be narrower or
+// shorter than it). A zero width or height is inferred from the other to
+// preserve aspect ratio exactly.
+func (p *ImageProcessor) Resize(width, height int) (ProcessedImage, error) {
+	return p.process(operation{kind: "resize", width: width, height: height})
+}
+
+// Fit scales the source to fit entirely within width x height without
+// upscaling, preserving aspect ratio and letterboxing with a transparent
+// (PNG) or black (JPEG) border to exactly fill the box.
+func (p *ImageProcessor) Fit(width, height int) (ProcessedImage, error) {
+	return p.process(operation{kind: "fit", width: width, height: height})
+}
+
+// Fill scales and crops the source to exactly width x height, cropping
+// around anchor ("center", "top", "bottom", "left", or "right") to avoid
+// distorting the aspect ratio. "smart" is accepted and crops exactly like
+// "center"; no content-aware weighting is applied.
+func (p *ImageProcessor) Fill(width, height int, anchor string) (ProcessedImage, error) {
+	if anchor == "" {
+		anchor = "center"
+	}
+	return p.process(operation{kind: "fill", width: width, height: height, anchor: anchor})
+}
+
+// GenerateResponsiveSet builds a Resize variant at each of responsiveWidths
+// (skipping any wider than the source) and returns them together as a
+// ready-to-paste srcSet
+func (p *ImageProcessor) GenerateResponsiveSet() (ProcessedImage, error) {
+	src, _, err := p.decodeSource()
+	if err != nil {
+		return ProcessedImage{}, err
+	}
+	srcBounds := src.Bounds()
+
+	var variants []ImageVariant
+	for _, w := range responsiveWidths {
+		if w > srcBounds.Dx() {
+			continue
+		}
+
+		result, err := p.Resize(w, 0)
+		if err != nil {
+			return ProcessedImage{}, err
+		}
+		variants = append(variants, result.Variants...)
+	}
+
+	if len(variants) == 0 {
+		// Source is narrower than every breakpoint; emit it as its own variant
+		result, err := p.Resize(srcBounds.Dx(), srcBounds.Dy())
+		if err != nil {
+			return ProcessedImage{}, err
+		}
+		variants = result.Variants
+	}
+
+	return p.buildResult(variants), nil
+}
+
+// operation is the cache key's parameter set: (kind, width, height, anchor)
+type operation struct {
+	kind   string
+	width  int
+	height int
+	anchor string
+}
+
+func (op operation) suffix() string {
+	name := fmt.Sprintf("%s_%dx%d", op.kind, op.width, op.height)
+	if op.anchor != "" {
+		name += "_" + op.anchor
+	}
+	return name
+}
+
+// process resolves op against the cache, generating and writing the variant
+// only on a cache miss, and returns it wrapped in a ProcessedImage
+func (p *ImageProcessor) process(op operation) (ProcessedImage, error) {
+	sourceBytes, err := os.ReadFile(p.sourcePath)
+	if err != nil {
+		return ProcessedImage{}, fmt.Errorf("failed to read source image: %w", err)
+	}
+
+	ext := strings.ToLower(filepath.Ext(p.sourcePath))
+	cacheDir, cacheFilename := p.cachePaths(sourceBytes, op, ext)
+	cachePath := filepath.Join(cacheDir, cacheFilename)
+
+	width, height, err := imageDimensions(cachePath)
+	if err != nil {
+		// Cache miss: decode, transform, encode, and write to cachePath
+		src, _, decodeErr := decodeImage(sourceBytes)
+		if decodeErr != nil {
+			return ProcessedImage{}, fmt.Errorf("failed to decode source image: %w", decodeErr)
+		}
+
+		transformed := applyOperation(src, op)
+		if err := os.MkdirAll(cacheDir, 0755); err != nil {
+			return ProcessedImage{}, fmt.Errorf("failed to create image cache directory: %w", err)
+		}
+		if err := encodeImage(cachePath, transformed, ext); err != nil {
+			return ProcessedImage{}, fmt.Errorf("failed to encode processed image: %w", err)
+		}
+
+		bounds := transformed.Bounds()
+		width, height = bounds.Dx(), bounds.Dy()
+	}
+
+	relPath, err := filepath.Rel(p.publicDir, cachePath)
+	if err != nil {
+		return ProcessedImage{}, err
+	}
+
+	variant := ImageVariant{
+		Path:   "/" + filepath.ToSlash(relPath),
+		Width:  width,
+		Height: height,
+	}
+	return p.buildResult([]ImageVariant{variant}), nil
+}
+
+// cachePaths returns the directory and filename process should read/write
+// for op against sourceBytes, per the scheme:
+// .layrr/cache/images/<sha256(source+op+ext)[:16]>/<op>_<w>x<h>[_<anchor>].<ext>
+func (p *ImageProcessor) cachePaths(sourceBytes []byte, op operation, ext string) (dir, filename string) {
+	h := sha256.New()
+	h.Write(sourceBytes)
+	h.Write([]byte(op.suffix()))
+	h.Write([]byte(ext))
+	hash := hex.EncodeToString(h.Sum(nil))[:16]
+
+	dir = filepath.Join(p.projectDir, layrrDirName, "cache", "images", hash)
+	filename = op.suffix() + ext
+	return dir, filename
+}
+
+// InvalidateCache removes the cache bucket (hash directory) for the current
+// source image contents, so overwriting a source with SaveImageToProject
+// doesn't keep serving stale derivatives under the old hash. Buckets for
+// since-changed source bytes are orphaned but harmless; they simply won't be
+// looked up again.
+func (p *ImageProcessor) InvalidateCache() error {
+	sourceBytes, err := os.ReadFile(p.sourcePath)
+	if err != nil {
+		return err
+	}
+
+	cacheRoot := filepath.Join(p.projectDir, layrrDirName, "cache", "images")
+	entries, err := os.ReadDir(cacheRoot)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		bucket := filepath.Join(cacheRoot, entry.Name())
+		variants, _ := os.ReadDir(bucket)
+		// cachePaths hashes source+op.suffix()+ext, i.e. the source plus the variant's filename
+		for _, v := range variants {
+			h := sha256.New()
+			h.Write(sourceBytes)
+			h.Write([]byte(v.Name()))
+			if hex.EncodeToString(h.Sum(nil))[:16] == entry.Name() {
+				if err := os.RemoveAll(bucket); err != nil {
+					return err
+				}
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+func (p *ImageProcessor) buildResult(variants []ImageVariant) ProcessedImage {
+	relSource, _ := filepath.Rel(p.publicDir, p.sourcePath)
+
+	srcSetParts := make([]string, len(variants))
+	width, height := 0, 0
+	for i, v := range variants {
+		srcSetParts[i] = fmt.Sprintf("%s %dw", v.Path, v.Width)
+		if v.Width > width {
+			width, height = v.Width, v.Height
+		}
+	}
+
+	return ProcessedImage{
+		OriginalPath: "/" + filepath.ToSlash(relSource),
+		Variants:     variants,
+		SrcSet:       strings.Join(srcSetParts, ", "),
+		Width:        width,
+		Height:       height,
+	}
+}
+
+func (p *ImageProcessor) decodeSource() (image.Image, string, error) {
+	data, err := os.ReadFile(p.sourcePath)
+	if err != nil {
+		return nil, "", err
+	}
+	return decodeImage(data)
+}
+
+// imageDimensions returns the pixel dimensions of the image at path without
+// fully decoding it, erroring if path doesn't exist or isn't a decodable
+// image (the signal process() uses to tell a cache hit from a cache miss)
+func imageDimensions(path string) (width, height int, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer f.Close()
+
+	cfg, _, err := image.DecodeConfig(f)
+	if err != nil {
+		return 0, 0, err
+	}
+	return cfg.Width, cfg.Height, nil
+}
+
+// decodeImage decodes JPEG or PNG source bytes into an image.Image
+func decodeImage(data []byte) (image.Image, string, error) {
+	return image.Decode(bytes.NewReader(data))
+}
+
+// encodeImage writes img to path in ext's format. WebP has no encoder in
+// the Go standard library (only golang.org/x/image/webp's decoder), so a
+// requested .webp target is encoded as PNG instead, under its requested
+// filename, which every browser still decodes correctly since browsers
+// sniff image content rather than trusting the extension.
+func encodeImage(path string, img image.Image, ext string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	switch ext {
+	case ".jpg", ".jpeg":
+		return jpeg.Encode(f, img, &jpeg.Options{Quality: 85})
+	default: // ".png", ".webp", ".gif", ".svg", and anything else
+		return png.Encode(f, img)
+	}
+}
+
+// widthHeightFromBox resolves Resize's box against the source's aspect
+// ratio, as Resize's doc promises: a full box is shrunk on its looser side,
+// and a zero width or height is inferred from the other
+func widthHeightFromBox(srcW, srcH, width, height int) (int, int) {
+	switch {
+	case width > 0 && height > 0:
+		if width*srcH <= height*srcW {
+			return width, int(float64(width) * float64(srcH) / float64(srcW))
+		}
+		return int(float64(height) * float64(srcW) / float64(srcH)), height
+	case width > 0:
+		return width, int(float64(width) * float64(srcH) / float64(srcW))
+	case height > 0:
+		return int(float64(height) * float64(srcW) / float64(srcH)), height
+	default:
+		return srcW, srcH
+	}
+}
@@ -1,78 +1,242 @@
 package proxy
 
 import (
+	"bufio"
 	"bytes"
+	"compress/flate"
 	"compress/gzip"
+	"compress/zlib"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
+
+	"github.com/andybalholm/brotli"
+	"golang.org/x/net/html"
 )
 
-// InjectScript injects JavaScript and CSS into HTML responses
-func InjectScript(resp *http.Response, baseURL string) error {
+// InjectScript injects JavaScript into HTML responses. It decodes gzip, br,
+// and deflate bodies, rewrites the markup with a streaming tokenizer, and
+// re-encodes the result back into the original Content-Encoding. token is
+// this run's session token (config.NewSessionToken), baked into the
+// injected live-event client so its requests satisfy the asset server's
+// requireWSToken/requireLiveEventsToken checks.
+func InjectScript(resp *http.Response, baseURL, token string) error {
 	// Only inject into HTML responses
 	contentType := resp.Header.Get("Content-Type")
 	if !strings.Contains(contentType, "text/html") {
 		return nil
 	}
 
-	// Check if response is compressed
 	contentEncoding := resp.Header.Get("Content-Encoding")
-	var bodyReader io.Reader = resp.Body
 
-	// Decompress if needed
-	if contentEncoding == "gzip" {
-		gzipReader, err := gzip.NewReader(resp.Body)
-		if err != nil {
-			return fmt.Errorf("failed to create gzip reader: %w", err)
-		}
-		defer gzipReader.Close()
-		bodyReader = gzipReader
+	bodyReader, err := decodeBody(resp.Body, contentEncoding)
+	if err != nil {
+		return fmt.Errorf("failed to decode response body (%s): %w", contentEncoding, err)
 	}
 
-	// Read the (potentially decompressed) response body
 	body, err := io.ReadAll(bodyReader)
 	if err != nil {
 		return fmt.Errorf("failed to read response body: %w", err)
 	}
 	resp.Body.Close()
 
-	// Remove Content-Encoding and Transfer-Encoding headers since we're sending uncompressed
-	resp.Header.Del("Content-Encoding")
-	resp.Header.Del("Transfer-Encoding")
-
 	// Skip injection if body is empty or too small to be valid HTML
 	if len(body) < 10 {
 		resp.Body = io.NopCloser(bytes.NewReader(body))
 		return nil
 	}
 
-	// Create injection tag for minimal Layrr (hover + selection only, ~1.36 KB)
-	// This minimal bundle only handles element highlighting and selection
-	// All UI controls are now in the sidebar
-	injection := fmt.Sprintf(`
-	<!-- Layrr - Minimal Element Selector -->
-	<script defer src="%s/inject-minimal.js"></script>
-`, baseURL)
-
-	// Try to inject before </body>, otherwise before </html>, otherwise at the end
-	bodyStr := string(body)
-	var modified string
-
-	if strings.Contains(bodyStr, "</body>") {
-		modified = strings.Replace(bodyStr, "</body>", injection+"</body>", 1)
-	} else if strings.Contains(bodyStr, "</html>") {
-		modified = strings.Replace(bodyStr, "</html>", injection+"</html>", 1)
-	} else {
-		modified = bodyStr + injection
+	modified, err := injectIntoHTML(body, baseURL, token)
+	if err != nil {
+		// Unparseable markup: serve the original body rather than fail the request
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		resp.ContentLength = int64(len(body))
+		return nil
 	}
 
-	// Update the response body
-	modifiedBytes := []byte(modified)
-	resp.Body = io.NopCloser(bytes.NewReader(modifiedBytes))
-	resp.ContentLength = int64(len(modifiedBytes))
-	resp.Header.Set("Content-Length", fmt.Sprintf("%d", len(modifiedBytes)))
+	encoded, err := encodeBody(modified, contentEncoding)
+	if err != nil {
+		return fmt.Errorf("failed to re-encode response body (%s): %w", contentEncoding, err)
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(encoded))
+	resp.ContentLength = int64(len(encoded))
+	resp.Header.Set("Content-Length", fmt.Sprintf("%d", len(encoded)))
+	resp.Header.Del("Transfer-Encoding")
 
 	return nil
 }
+
+// decodeBody returns a reader over the decompressed body for the given
+// Content-Encoding, or body itself when encoding is empty/unrecognized
+func decodeBody(body io.Reader, encoding string) (io.Reader, error) {
+	switch encoding {
+	case "gzip":
+		return gzip.NewReader(body)
+	case "br":
+		return brotli.NewReader(body), nil
+	case "deflate":
+		return decodeDeflate(body)
+	default:
+		return body, nil
+	}
+}
+
+// decodeDeflate decodes a "deflate" Content-Encoding body. The spec calls for
+// zlib-wrapped DEFLATE, but some servers send raw DEFLATE despite that, so
+// sniff the zlib header and fall back to raw flate when it's missing.
+func decodeDeflate(body io.Reader) (io.Reader, error) {
+	buffered := bufio.NewReader(body)
+
+	if peek, err := buffered.Peek(2); err == nil && len(peek) == 2 {
+		cmf, flg := peek[0], peek[1]
+		if cmf&0x0f == 8 && (int(cmf)*256+int(flg))%31 == 0 {
+			return zlib.NewReader(buffered)
+		}
+	}
+
+	return flate.NewReader(buffered), nil
+}
+
+// encodeBody re-compresses body in the given Content-Encoding, or returns it
+// unchanged when encoding is empty/unrecognized
+func encodeBody(body []byte, encoding string) ([]byte, error) {
+	var buf bytes.Buffer
+
+	var w io.WriteCloser
+	switch encoding {
+	case "gzip":
+		w = gzip.NewWriter(&buf)
+	case "br":
+		w = brotli.NewWriter(&buf)
+	case "deflate":
+		w = zlib.NewWriter(&buf) // re-encode as zlib-wrapped, the spec-compliant form
+	default:
+		return body, nil
+	}
+
+	if _, err := w.Write(body); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// injectIntoHTML streams body through an HTML tokenizer and inserts the
+// injection tag right before </body> (or </html>, or at the end if neither
+// closing tag appears), so markup inside comments, CDATA, or inline <script>
+// strings never falsely matches the way a raw strings.Replace would.
+func injectIntoHTML(body []byte, baseURL, token string) ([]byte, error) {
+	injection := buildInjectionTag(baseURL, token, extractScriptNonce(body))
+
+	z := html.NewTokenizer(bytes.NewReader(body))
+	var out bytes.Buffer
+	injected := false
+
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			if err := z.Err(); err != io.EOF {
+				return nil, err
+			}
+			break
+		}
+
+		if tt == html.EndTagToken && !injected {
+			if name, _ := z.TagName(); string(name) == "body" || string(name) == "html" {
+				out.WriteString(injection)
+				injected = true
+			}
+		}
+
+		out.Write(z.Raw())
+	}
+
+	if !injected {
+		out.WriteString(injection)
+	}
+
+	return out.Bytes(), nil
+}
+
+// extractScriptNonce returns the nonce attribute of the first <script> tag
+// that has one, so the injected tag can reuse it under a strict CSP
+// (script-src 'nonce-...') instead of being silently blocked by the browser.
+func extractScriptNonce(body []byte) string {
+	z := html.NewTokenizer(bytes.NewReader(body))
+
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			return ""
+		}
+		if tt != html.StartTagToken && tt != html.SelfClosingTagToken {
+			continue
+		}
+
+		name, hasAttr := z.TagName()
+		if string(name) != "script" || !hasAttr {
+			continue
+		}
+
+		for {
+			key, val, more := z.TagAttr()
+			if string(key) == "nonce" {
+				return string(val)
+			}
+			if !more {
+				break
+			}
+		}
+	}
+}
+
+// buildInjectionTag renders the injected <script> tags: Layrr's minimal
+// element selector bundle (hover + selection only, ~1.36 KB), plus a tiny
+// client that subscribes to /__layrr/ws/events and re-dispatches each event
+// as a CustomEvent on window for the sidebar to react to.
+func buildInjectionTag(baseURL, token, nonce string) string {
+	nonceAttr := ""
+	if nonce != "" {
+		nonceAttr = fmt.Sprintf(` nonce="%s"`, nonce)
+	}
+
+	return fmt.Sprintf(`
+	<!-- Layrr - Minimal Element Selector -->
+	<script defer%[1]s src="%[2]s/inject-minimal.js"></script>
+	<!-- Layrr - Live Event Client -->
+	<script%[1]s>%[3]s</script>
+`, nonceAttr, baseURL, buildEventClientScript(baseURL, token))
+}
+
+// buildEventClientScript returns a small auto-reconnecting client for
+// /__layrr/ws/events: it opens a WebSocket (falling back to EventSource if
+// WebSocket isn't available, or the connection never opens), and re-dispatches
+// each {type, ts, payload} envelope as window.dispatchEvent(new
+// CustomEvent("layrr:"+type, {detail: payload})). token is this run's
+// session token, which the asset server's requireWSToken/
+// requireLiveEventsToken require on this endpoint: the WebSocket path sends
+// it as a Sec-WebSocket-Protocol (the only header a browser WebSocket
+// client can set), and the EventSource fallback - which can't set that
+// header either - sends it as a ?token= query parameter instead.
+func buildEventClientScript(baseURL, token string) string {
+	return fmt.Sprintf(`(function(){
+  var base=%[1]q,token=%[2]q,wsURL=base.replace(/^http/,'ws')+'/__layrr/ws/events',delay=500;
+  function dispatch(evt){try{var m=JSON.parse(evt.data);window.dispatchEvent(new CustomEvent('layrr:'+m.type,{detail:m.payload}));}catch(e){}}
+  function reconnect(){setTimeout(connect,delay);delay=Math.min(delay*2,10000);}
+  function connectSSE(){var es=new EventSource(base+'/__layrr/ws/events?token='+encodeURIComponent(token));es.onmessage=dispatch;es.onerror=function(){es.close();reconnect();};}
+  function connect(){
+    if(!window.WebSocket){connectSSE();return;}
+    var ws=new WebSocket(wsURL,[token]),opened=false;
+    ws.onmessage=dispatch;
+    ws.onopen=function(){opened=true;delay=500;};
+    ws.onclose=function(){if(opened){reconnect();}else{connectSSE();}};
+    ws.onerror=function(){ws.close();};
+  }
+  connect();
+})();`, baseURL, token)
+}
@@ -0,0 +1,14 @@
+// Package netstat is a pure-Go socket-table reader, used in place of shelling
+// out to lsof/netstat/tasklist. Those binaries aren't guaranteed to exist on
+// slim containers and Windows Server Core, and parsing their output is both
+// locale-dependent and slow (~100ms per scan). Each platform file implements
+// ListListeners by reading the OS's socket table directly.
+package netstat
+
+// Listener describes one process listening on a TCP port
+type Listener struct {
+	Port        int    `json:"port"`
+	PID         int    `json:"pid"`
+	ProcessName string `json:"processName"`
+	WorkDir     string `json:"workDir"`
+}
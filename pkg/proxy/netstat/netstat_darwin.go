@@ -0,0 +1,141 @@
+//go:build darwin
+
+package netstat
+
+/*
+#include <stdlib.h>
+#include <string.h>
+#include <libproc.h>
+#include <sys/proc_info.h>
+#include <netinet/in.h>
+*/
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// ListListeners enumerates TCP listening sockets on macOS via the libproc
+// syscalls: proc_listpids to get every running PID, proc_pidinfo(PROC_PIDLISTFDS)
+// to list each process's file descriptors, and proc_pidfdinfo(PROC_PIDFDSOCKETINFO)
+// to inspect the ones that are sockets in the TCP LISTEN state.
+func ListListeners() ([]Listener, error) {
+	pids, err := listPIDs()
+	if err != nil {
+		return nil, err
+	}
+
+	var listeners []Listener
+	for _, pid := range pids {
+		fds, err := listFDs(pid)
+		if err != nil {
+			continue // process exited mid-scan, or we lack the entitlement to inspect it
+		}
+
+		for _, fd := range fds {
+			if fd.proc_fdtype != C.PROX_FDTYPE_SOCKET {
+				continue
+			}
+
+			port, ok := tcpListenPort(pid, fd.proc_fd)
+			if !ok {
+				continue
+			}
+
+			listeners = append(listeners, Listener{
+				Port:        port,
+				PID:         int(pid),
+				ProcessName: processName(pid),
+				WorkDir:     processWorkDir(pid),
+			})
+		}
+	}
+
+	return listeners, nil
+}
+
+// listPIDs returns every running PID via proc_listpids(PROC_ALL_PIDS)
+func listPIDs() ([]C.int, error) {
+	n := C.proc_listpids(C.PROC_ALL_PIDS, 0, nil, 0)
+	if n <= 0 {
+		return nil, fmt.Errorf("proc_listpids failed to size the pid buffer")
+	}
+
+	buf := make([]C.int, n)
+	n = C.proc_listpids(C.PROC_ALL_PIDS, 0, unsafe.Pointer(&buf[0]), C.int(len(buf))*C.int(unsafe.Sizeof(C.int(0))))
+	if n <= 0 {
+		return nil, fmt.Errorf("proc_listpids failed to fill the pid buffer")
+	}
+
+	count := int(n) / int(unsafe.Sizeof(C.int(0)))
+	pids := make([]C.int, 0, count)
+	for _, pid := range buf[:count] {
+		if pid > 0 {
+			pids = append(pids, pid)
+		}
+	}
+	return pids, nil
+}
+
+// listFDs returns every file descriptor proc_pidinfo(PROC_PIDLISTFDS) reports for pid
+func listFDs(pid C.int) ([]C.struct_proc_fdinfo, error) {
+	n := C.proc_pidinfo(pid, C.PROC_PIDLISTFDS, 0, nil, 0)
+	if n <= 0 {
+		return nil, fmt.Errorf("proc_pidinfo(PROC_PIDLISTFDS) failed for pid %d", pid)
+	}
+
+	count := int(n) / int(unsafe.Sizeof(C.struct_proc_fdinfo{}))
+	buf := make([]C.struct_proc_fdinfo, count)
+	n = C.proc_pidinfo(pid, C.PROC_PIDLISTFDS, 0, unsafe.Pointer(&buf[0]), n)
+	if n <= 0 {
+		return nil, fmt.Errorf("proc_pidinfo(PROC_PIDLISTFDS) failed to fill buffer for pid %d", pid)
+	}
+
+	return buf, nil
+}
+
+// tcpListenPort inspects one socket fd via proc_pidfdinfo(PROC_PIDFDSOCKETINFO)
+// and returns its local port if it's a TCP socket in the LISTEN state
+func tcpListenPort(pid, fd C.int) (int, bool) {
+	var info C.struct_socket_fdinfo
+	n := C.proc_pidfdinfo(pid, fd, C.PROC_PIDFDSOCKETINFO, unsafe.Pointer(&info), C.int(unsafe.Sizeof(info)))
+	if n <= 0 {
+		return 0, false
+	}
+
+	if info.psi.soi_kind != C.SOCKINFO_TCP {
+		return 0, false
+	}
+
+	tcpInfo := (*C.struct_tcp_sockinfo)(unsafe.Pointer(&info.psi.soi_proto[0]))
+	if tcpInfo.tcpsi_state != C.TSI_S_LISTEN {
+		return 0, false
+	}
+
+	port := int(C.ntohs(C.ushort(info.psi.soi_proto_local_port)))
+	return port, port != 0
+}
+
+// processName reads the BSD short process name via proc_name
+func processName(pid C.int) string {
+	buf := make([]byte, 256)
+	n := C.proc_name(pid, unsafe.Pointer(&buf[0]), C.uint32_t(len(buf)))
+	if n <= 0 {
+		return "unknown"
+	}
+	return string(buf[:n])
+}
+
+// processWorkDir reads the process's current working directory via
+// proc_pidinfo(PROC_PIDVNODEPATHINFO)
+func processWorkDir(pid C.int) string {
+	var info C.struct_proc_vnodepathinfo
+	n := C.proc_pidinfo(pid, C.PROC_PIDVNODEPATHINFO, 0, unsafe.Pointer(&info), C.int(unsafe.Sizeof(info)))
+	if n <= 0 {
+		return ""
+	}
+
+	path := C.GoString(&info.pvi_cdir.vip_path[0])
+	return path
+}
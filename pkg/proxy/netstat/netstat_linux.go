@@ -0,0 +1,147 @@
+package netstat
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// tcpStateListen is the hex socket-state value /proc/net/tcp[6] uses for LISTEN
+const tcpStateListen = "0A"
+
+// ListListeners enumerates TCP listening sockets on Linux by reading the
+// inode of every LISTEN socket out of /proc/net/tcp and /proc/net/tcp6, then
+// walking /proc/<pid>/fd/* symlinks ("socket:[<inode>]") to find the owning process.
+func ListListeners() ([]Listener, error) {
+	inodeToPort, err := parseListeningInodes("/proc/net/tcp")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read /proc/net/tcp: %w", err)
+	}
+
+	if inodes6, err := parseListeningInodes("/proc/net/tcp6"); err == nil {
+		for inode, port := range inodes6 {
+			inodeToPort[inode] = port
+		}
+	}
+
+	if len(inodeToPort) == 0 {
+		return nil, nil
+	}
+
+	inodeToPID, err := mapInodesToPIDs(inodeToPort)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan /proc for socket owners: %w", err)
+	}
+
+	listeners := make([]Listener, 0, len(inodeToPID))
+	for inode, pid := range inodeToPID {
+		listeners = append(listeners, Listener{
+			Port:        inodeToPort[inode],
+			PID:         pid,
+			ProcessName: processName(pid),
+			WorkDir:     processWorkDir(pid),
+		})
+	}
+
+	return listeners, nil
+}
+
+// parseListeningInodes reads a /proc/net/tcp[6] table and returns inode -> local
+// port for every socket currently in the LISTEN state
+func parseListeningInodes(path string) (map[string]int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	inodes := make(map[string]int)
+	scanner := bufio.NewScanner(f)
+	scanner.Scan() // header line: "sl  local_address rem_address st ..."
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 10 || fields[3] != tcpStateListen {
+			continue
+		}
+
+		parts := strings.Split(fields[1], ":") // "0100007F:1F90"
+		if len(parts) != 2 {
+			continue
+		}
+		port, err := strconv.ParseInt(parts[1], 16, 32)
+		if err != nil {
+			continue
+		}
+
+		if inode := fields[9]; inode != "0" {
+			inodes[inode] = int(port)
+		}
+	}
+
+	return inodes, scanner.Err()
+}
+
+// mapInodesToPIDs walks every /proc/<pid>/fd entry looking for the
+// "socket:[<inode>]" symlinks that match one of the wanted inodes
+func mapInodesToPIDs(wanted map[string]int) (map[string]int, error) {
+	procEntries, err := os.ReadDir("/proc")
+	if err != nil {
+		return nil, err
+	}
+
+	found := make(map[string]int)
+	for _, entry := range procEntries {
+		if len(found) == len(wanted) {
+			break
+		}
+
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue // not a PID directory
+		}
+
+		fdDir := fmt.Sprintf("/proc/%d/fd", pid)
+		fds, err := os.ReadDir(fdDir)
+		if err != nil {
+			continue // process exited mid-scan, or we lack permission
+		}
+
+		for _, fd := range fds {
+			link, err := os.Readlink(filepath.Join(fdDir, fd.Name()))
+			if err != nil || !strings.HasPrefix(link, "socket:[") {
+				continue
+			}
+
+			inode := strings.TrimSuffix(strings.TrimPrefix(link, "socket:["), "]")
+			if _, wantIt := wanted[inode]; !wantIt {
+				continue
+			}
+			if _, already := found[inode]; !already {
+				found[inode] = pid
+			}
+		}
+	}
+
+	return found, nil
+}
+
+// processName reads the short command name for pid from /proc/<pid>/comm
+func processName(pid int) string {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/comm", pid))
+	if err != nil {
+		return "unknown"
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// processWorkDir resolves the /proc/<pid>/cwd symlink
+func processWorkDir(pid int) string {
+	cwd, err := os.Readlink(fmt.Sprintf("/proc/%d/cwd", pid))
+	if err != nil {
+		return ""
+	}
+	return cwd
+}
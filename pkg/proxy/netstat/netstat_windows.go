@@ -0,0 +1,109 @@
+//go:build windows
+
+package netstat
+
+import (
+	"fmt"
+	"path/filepath"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	modIphlpapi               = syscall.NewLazyDLL("iphlpapi.dll")
+	procGetExtendedTCPTable   = modIphlpapi.NewProc("GetExtendedTcpTable")
+	modKernel32               = syscall.NewLazyDLL("kernel32.dll")
+	procOpenProcess           = modKernel32.NewProc("OpenProcess")
+	procQueryFullProcessImage = modKernel32.NewProc("QueryFullProcessImageNameW")
+	procCloseHandle           = modKernel32.NewProc("CloseHandle")
+)
+
+const (
+	afInet                   = 2 // AF_INET
+	tcpTableOwnerPIDListener = 3 // TCP_TABLE_OWNER_PID_LISTENER: listening sockets only
+	processQueryLimited      = 0x1000
+)
+
+// mibTCPRowOwnerPID mirrors the Win32 MIB_TCPROW_OWNER_PID struct
+type mibTCPRowOwnerPID struct {
+	State      uint32
+	LocalAddr  uint32
+	LocalPort  uint32 // big-endian, low 16 bits
+	RemoteAddr uint32
+	RemotePort uint32
+	OwningPID  uint32
+}
+
+// ListListeners enumerates TCP listening sockets on Windows via
+// GetExtendedTcpTable(TCP_TABLE_OWNER_PID_LISTENER), then resolves each
+// owning PID's executable path with QueryFullProcessImageName.
+func ListListeners() ([]Listener, error) {
+	table, err := getTCPListenerTable()
+	if err != nil {
+		return nil, err
+	}
+
+	listeners := make([]Listener, 0, len(table))
+	for _, row := range table {
+		port := int(((row.LocalPort & 0xff) << 8) | ((row.LocalPort >> 8) & 0xff))
+		pid := int(row.OwningPID)
+
+		exePath := processExePath(pid)
+		listeners = append(listeners, Listener{
+			Port:        port,
+			PID:         pid,
+			ProcessName: filepath.Base(exePath),
+			WorkDir:     filepath.Dir(exePath),
+		})
+	}
+
+	return listeners, nil
+}
+
+// getTCPListenerTable calls GetExtendedTcpTable twice: once to size the
+// buffer, once to fill it with MIB_TCPROW_OWNER_PID entries
+func getTCPListenerTable() ([]mibTCPRowOwnerPID, error) {
+	var size uint32
+	procGetExtendedTCPTable.Call(0, uintptr(unsafe.Pointer(&size)), 0, afInet, tcpTableOwnerPIDListener, 0)
+
+	buf := make([]byte, size)
+	ret, _, _ := procGetExtendedTCPTable.Call(
+		uintptr(unsafe.Pointer(&buf[0])),
+		uintptr(unsafe.Pointer(&size)),
+		0, afInet, tcpTableOwnerPIDListener, 0)
+	if ret != 0 {
+		return nil, fmt.Errorf("GetExtendedTcpTable failed: error code %d", ret)
+	}
+
+	numEntries := *(*uint32)(unsafe.Pointer(&buf[0]))
+	rows := make([]mibTCPRowOwnerPID, numEntries)
+	rowSize := unsafe.Sizeof(mibTCPRowOwnerPID{})
+	base := uintptr(unsafe.Pointer(&buf[0])) + unsafe.Sizeof(numEntries)
+	for i := uint32(0); i < numEntries; i++ {
+		rows[i] = *(*mibTCPRowOwnerPID)(unsafe.Pointer(base + uintptr(i)*rowSize))
+	}
+
+	return rows, nil
+}
+
+// processExePath resolves a PID's executable path via
+// OpenProcess(PROCESS_QUERY_LIMITED_INFORMATION) + QueryFullProcessImageName
+func processExePath(pid int) string {
+	handle, _, _ := procOpenProcess.Call(processQueryLimited, 0, uintptr(pid))
+	if handle == 0 {
+		return ""
+	}
+	defer procCloseHandle.Call(handle)
+
+	buf := make([]uint16, syscall.MAX_PATH)
+	size := uint32(len(buf))
+	ret, _, _ := procQueryFullProcessImage.Call(
+		handle, 0,
+		uintptr(unsafe.Pointer(&buf[0])),
+		uintptr(unsafe.Pointer(&size)))
+	if ret == 0 {
+		return ""
+	}
+
+	return syscall.UTF16ToString(buf[:size])
+}
@@ -5,10 +5,11 @@ import (
 	"net"
 	"os/exec"
 	"path/filepath"
-	"runtime"
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/thetronjohnson/layrr/pkg/proxy/netstat"
 )
 
 // PortInfo contains information about a port and its associated process
@@ -24,6 +25,10 @@ func DetectPortsWithInfo() []PortInfo {
 	commonPorts := []int{5173, 3000, 8080, 4200, 8000, 5000, 8888, 3001, 4000, 9000}
 	portInfos := []PortInfo{}
 
+	// byPort is nil if the native reader itself errored out (e.g. /proc
+	// unreadable); each port below falls back to lsof individually in that case
+	byPort, _ := listenersByPort()
+
 	for _, port := range commonPorts {
 		// First check if port is open
 		addr := fmt.Sprintf("localhost:%d", port)
@@ -33,9 +38,17 @@ func DetectPortsWithInfo() []PortInfo {
 		}
 		conn.Close()
 
-		// Port is open, try to get process info
-		info := getProcessInfo(port)
-		if info != nil {
+		if listener, ok := byPort[port]; ok {
+			portInfos = append(portInfos, PortInfo{
+				Port:        listener.Port,
+				ProcessName: listener.ProcessName,
+				WorkDir:     cleanPath(listener.WorkDir),
+				PID:         listener.PID,
+			})
+			continue
+		}
+
+		if info := getProcessInfoLsofFallback(port); info != nil {
 			portInfos = append(portInfos, *info)
 		} else {
 			// Port is open but couldn't get process info
@@ -51,21 +64,26 @@ func DetectPortsWithInfo() []PortInfo {
 	return portInfos
 }
 
-// getProcessInfo retrieves process information for a given port
-func getProcessInfo(port int) *PortInfo {
-	switch runtime.GOOS {
-	case "darwin", "linux":
-		return getProcessInfoUnix(port)
-	case "windows":
-		return getProcessInfoWindows(port)
-	default:
-		return nil
+// listenersByPort indexes netstat.ListListeners() by port for O(1) lookups
+func listenersByPort() (map[int]netstat.Listener, error) {
+	listeners, err := netstat.ListListeners()
+	if err != nil {
+		return nil, err
+	}
+
+	byPort := make(map[int]netstat.Listener, len(listeners))
+	for _, l := range listeners {
+		byPort[l.Port] = l
 	}
+	return byPort, nil
 }
 
-// getProcessInfoUnix retrieves process info on macOS and Linux using lsof
-func getProcessInfoUnix(port int) *PortInfo {
-	// Use lsof to find the process listening on the port
+// getProcessInfoLsofFallback shells out to lsof for a single port. It runs
+// for any open port netstat.ListListeners reported no listener for (every
+// port, when ListListeners errored outright), so it's the exception path
+// rather than the common one; lsof itself may also be missing on minimal
+// containers, in which case this just returns nil.
+func getProcessInfoLsofFallback(port int) *PortInfo {
 	cmd := exec.Command("lsof", "-i", fmt.Sprintf(":%d", port), "-sTCP:LISTEN", "-n", "-P")
 	output, err := cmd.CombinedOutput()
 	if err != nil {
@@ -91,24 +109,15 @@ func getProcessInfoUnix(port int) *PortInfo {
 		}
 
 		processName := fields[0]
-		pidStr := fields[1]
-		pid, err := strconv.Atoi(pidStr)
+		pid, err := strconv.Atoi(fields[1])
 		if err != nil {
 			continue
 		}
 
-		// Get working directory for the process
-		workDir := getWorkingDirectory(pid)
-
-		// Clean up the working directory path
-		if workDir != "" {
-			workDir = cleanPath(workDir)
-		}
-
 		return &PortInfo{
 			Port:        port,
 			ProcessName: processName,
-			WorkDir:     workDir,
+			WorkDir:     cleanPath(lsofWorkingDirectory(pid)),
 			PID:         pid,
 		}
 	}
@@ -116,109 +125,21 @@ func getProcessInfoUnix(port int) *PortInfo {
 	return nil
 }
 
-// getProcessInfoWindows retrieves process info on Windows using netstat
-func getProcessInfoWindows(port int) *PortInfo {
-	// Use netstat to find the process
-	cmd := exec.Command("netstat", "-ano")
+// lsofWorkingDirectory shells out to lsof to get a process's cwd, for the fallback path only
+func lsofWorkingDirectory(pid int) string {
+	cmd := exec.Command("lsof", "-a", "-p", strconv.Itoa(pid), "-d", "cwd", "-Fn")
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return nil
-	}
-
-	// Parse netstat output to find PID
-	lines := strings.Split(string(output), "\n")
-	var pid int
-	for _, line := range lines {
-		if strings.Contains(line, fmt.Sprintf(":%d", port)) && strings.Contains(line, "LISTENING") {
-			fields := strings.Fields(line)
-			if len(fields) > 0 {
-				pidStr := fields[len(fields)-1]
-				pid, _ = strconv.Atoi(pidStr)
-				break
-			}
-		}
-	}
-
-	if pid == 0 {
-		return nil
-	}
-
-	// Use tasklist to get process name
-	cmd = exec.Command("tasklist", "/FI", fmt.Sprintf("PID eq %d", pid), "/FO", "CSV", "/NH")
-	output, err = cmd.CombinedOutput()
-	if err != nil {
-		return nil
-	}
-
-	// Parse tasklist output
-	fields := strings.Split(string(output), ",")
-	if len(fields) < 1 {
-		return nil
-	}
-
-	processName := strings.Trim(fields[0], "\"")
-	workDir := getWorkingDirectory(pid)
-
-	return &PortInfo{
-		Port:        port,
-		ProcessName: processName,
-		WorkDir:     cleanPath(workDir),
-		PID:         pid,
-	}
-}
-
-// getWorkingDirectory gets the working directory for a process by PID
-func getWorkingDirectory(pid int) string {
-	switch runtime.GOOS {
-	case "darwin":
-		// On macOS, use lsof to get the current working directory
-		cmd := exec.Command("lsof", "-a", "-p", strconv.Itoa(pid), "-d", "cwd", "-Fn")
-		output, err := cmd.CombinedOutput()
-		if err != nil {
-			return ""
-		}
-
-		// Parse lsof output (format: n<path>)
-		lines := strings.Split(string(output), "\n")
-		for _, line := range lines {
-			if strings.HasPrefix(line, "n") {
-				return strings.TrimPrefix(line, "n")
-			}
-		}
 		return ""
+	}
 
-	case "linux":
-		// On Linux, read /proc/<pid>/cwd symlink
-		cwdPath := fmt.Sprintf("/proc/%d/cwd", pid)
-		cmd := exec.Command("readlink", cwdPath)
-		output, err := cmd.CombinedOutput()
-		if err != nil {
-			return ""
-		}
-		return strings.TrimSpace(string(output))
-
-	case "windows":
-		// On Windows, use wmic to get working directory
-		cmd := exec.Command("wmic", "process", "where", fmt.Sprintf("ProcessId=%d", pid), "get", "ExecutablePath", "/format:list")
-		output, err := cmd.CombinedOutput()
-		if err != nil {
-			return ""
-		}
-
-		// Parse wmic output
-		lines := strings.Split(string(output), "\n")
-		for _, line := range lines {
-			if strings.HasPrefix(line, "ExecutablePath=") {
-				exePath := strings.TrimPrefix(line, "ExecutablePath=")
-				exePath = strings.TrimSpace(exePath)
-				return filepath.Dir(exePath)
-			}
+	// Parse lsof output (format: n<path>)
+	for _, line := range strings.Split(string(output), "\n") {
+		if strings.HasPrefix(line, "n") {
+			return strings.TrimPrefix(line, "n")
 		}
-		return ""
-
-	default:
-		return ""
 	}
+	return ""
 }
 
 // cleanPath cleans the path (returns full path, not shortened)
@@ -230,16 +151,3 @@ func cleanPath(path string) string {
 	// Clean the path and return full path (don't shorten with ~)
 	return filepath.Clean(path)
 }
-
-// getEnvVar is a helper to get environment variables (for testing)
-func getEnvVar(key string) string {
-	cmd := exec.Command("sh", "-c", fmt.Sprintf("echo $%s", key))
-	if runtime.GOOS == "windows" {
-		cmd = exec.Command("cmd", "/c", fmt.Sprintf("echo %%%s%%", key))
-	}
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return ""
-	}
-	return strings.TrimSpace(string(output))
-}
@@ -0,0 +1,158 @@
+package proxy
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+)
+
+// applyOperation dispatches to the resize variant requested by op.kind
+func applyOperation(src image.Image, op operation) image.Image {
+	switch op.kind {
+	case "fit":
+		return fitImage(src, op.width, op.height)
+	case "fill":
+		return fillImage(src, op.width, op.height, op.anchor)
+	default: // "resize"
+		w, h := widthHeightFromBox(src.Bounds().Dx(), src.Bounds().Dy(), op.width, op.height)
+		return resizeImage(src, w, h)
+	}
+}
+
+// resizeImage scales src to exactly width x height using bilinear
+// interpolation. The standard library has no resampler of its own, so this
+// is a small hand-rolled one rather than pulling in a dependency this
+// module otherwise has no reason to carry.
+func resizeImage(src image.Image, width, height int) *image.NRGBA {
+	if width <= 0 {
+		width = 1
+	}
+	if height <= 0 {
+		height = 1
+	}
+
+	bounds := src.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+
+	scaleX := float64(srcW) / float64(width)
+	scaleY := float64(srcH) / float64(height)
+
+	for y := 0; y < height; y++ {
+		srcY := (float64(y)+0.5)*scaleY - 0.5
+		for x := 0; x < width; x++ {
+			srcX := (float64(x)+0.5)*scaleX - 0.5
+			dst.SetNRGBA(x, y, bilinearSample(src, bounds, srcX, srcY))
+		}
+	}
+
+	return dst
+}
+
+// bilinearSample samples src at floating-point coordinates (x, y) in
+// bounds' coordinate space, clamping to the edge past the image border
+func bilinearSample(src image.Image, bounds image.Rectangle, x, y float64) color.NRGBA {
+	x0 := int(math.Floor(x))
+	y0 := int(math.Floor(y))
+	fx := x - float64(x0)
+	fy := y - float64(y0)
+
+	c00 := nrgbaAt(src, bounds, x0, y0)
+	c10 := nrgbaAt(src, bounds, x0+1, y0)
+	c01 := nrgbaAt(src, bounds, x0, y0+1)
+	c11 := nrgbaAt(src, bounds, x0+1, y0+1)
+
+	return color.NRGBA{
+		R: lerp2D(c00.R, c10.R, c01.R, c11.R, fx, fy),
+		G: lerp2D(c00.G, c10.G, c01.G, c11.G, fx, fy),
+		B: lerp2D(c00.B, c10.B, c01.B, c11.B, fx, fy),
+		A: lerp2D(c00.A, c10.A, c01.A, c11.A, fx, fy),
+	}
+}
+
+func nrgbaAt(src image.Image, bounds image.Rectangle, x, y int) color.NRGBA {
+	if x < bounds.Min.X {
+		x = bounds.Min.X
+	}
+	if x >= bounds.Max.X {
+		x = bounds.Max.X - 1
+	}
+	if y < bounds.Min.Y {
+		y = bounds.Min.Y
+	}
+	if y >= bounds.Max.Y {
+		y = bounds.Max.Y - 1
+	}
+	return color.NRGBAModel.Convert(src.At(x, y)).(color.NRGBA)
+}
+
+func lerp2D(c00, c10, c01, c11 uint8, fx, fy float64) uint8 {
+	top := float64(c00)*(1-fx) + float64(c10)*fx
+	bottom := float64(c01)*(1-fx) + float64(c11)*fx
+	return uint8(top*(1-fy) + bottom*fy)
+}
+
+// fitImage scales src to fit within width x height without upscaling,
+// preserving aspect ratio, and letterboxes the rest of the box
+func fitImage(src image.Image, width, height int) *image.NRGBA {
+	bounds := src.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+
+	scale := math.Min(float64(width)/float64(srcW), float64(height)/float64(srcH))
+	if scale > 1 {
+		scale = 1 // never upscale
+	}
+
+	scaledW := int(float64(srcW) * scale)
+	scaledH := int(float64(srcH) * scale)
+	scaled := resizeImage(src, scaledW, scaledH)
+
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	offsetX := (width - scaledW) / 2
+	offsetY := (height - scaledH) / 2
+	draw.Draw(dst, image.Rect(offsetX, offsetY, offsetX+scaledW, offsetY+scaledH), scaled, image.Point{}, draw.Src)
+
+	return dst
+}
+
+// fillImage scales src to cover width x height, then crops around anchor to
+// exactly that size
+func fillImage(src image.Image, width, height int, anchor string) *image.NRGBA {
+	bounds := src.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+
+	scale := math.Max(float64(width)/float64(srcW), float64(height)/float64(srcH))
+	scaledW := int(math.Ceil(float64(srcW) * scale))
+	scaledH := int(math.Ceil(float64(srcH) * scale))
+	scaled := resizeImage(src, scaledW, scaledH)
+
+	x0, y0 := cropOrigin(scaledW, scaledH, width, height, anchor)
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(dst, dst.Bounds(), scaled, image.Pt(x0, y0), draw.Src)
+
+	return dst
+}
+
+// cropOrigin returns the top-left corner of a width x height crop within a
+// scaledW x scaledH image, per anchor. "smart" is documented as an alias for
+// "center": a true content-aware crop needs saliency analysis this package
+// has no dependency-free way to do, so it falls back explicitly rather than
+// silently behaving like every other anchor without saying so.
+func cropOrigin(scaledW, scaledH, width, height int, anchor string) (x, y int) {
+	maxX := scaledW - width
+	maxY := scaledH - height
+
+	switch anchor {
+	case "top":
+		return maxX / 2, 0
+	case "bottom":
+		return maxX / 2, maxY
+	case "left":
+		return 0, maxY / 2
+	case "right":
+		return maxX, maxY / 2
+	default: // "center", "smart"
+		return maxX / 2, maxY / 2
+	}
+}
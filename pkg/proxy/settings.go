@@ -0,0 +1,48 @@
+package proxy
+
+import "net/http"
+
+// Settings describes per-project request/response rewriting and access
+// control applied to the proxied connection: headers to add to every
+// forwarded request, response headers to override, cookies to force, and an
+// optional Host allowlist. Persisted per-project by config.ProjectState and
+// loaded once at startup (see assetserver.Server.SetProxySettings).
+type Settings struct {
+	ExtraHeaders    map[string]string `json:"extraHeaders,omitempty"`
+	HeaderOverrides map[string]string `json:"headerOverrides,omitempty"`
+	CookieOverrides map[string]string `json:"cookieOverrides,omitempty"`
+	AllowedHosts    []string          `json:"allowedHosts,omitempty"`
+}
+
+// IsHostAllowed reports whether host may be forwarded to the dev server. An
+// empty AllowedHosts allows every host, which is the default when no
+// allowlist has been configured.
+func (s Settings) IsHostAllowed(host string) bool {
+	if len(s.AllowedHosts) == 0 {
+		return true
+	}
+	for _, allowed := range s.AllowedHosts {
+		if allowed == host {
+			return true
+		}
+	}
+	return false
+}
+
+// ApplyToRequest sets ExtraHeaders and CookieOverrides on req before it's
+// forwarded to the dev server
+func (s Settings) ApplyToRequest(req *http.Request) {
+	for name, value := range s.ExtraHeaders {
+		req.Header.Set(name, value)
+	}
+	for name, value := range s.CookieOverrides {
+		req.AddCookie(&http.Cookie{Name: name, Value: value})
+	}
+}
+
+// ApplyToResponse sets HeaderOverrides on resp after the dev server has responded
+func (s Settings) ApplyToResponse(resp *http.Response) {
+	for name, value := range s.HeaderOverrides {
+		resp.Header.Set(name, value)
+	}
+}
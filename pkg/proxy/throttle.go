@@ -0,0 +1,164 @@
+package proxy
+
+import (
+	"math/rand"
+	"net"
+	"sync"
+	"time"
+)
+
+// NetworkProfile describes a bandwidth/latency/loss condition to emulate while
+// previewing, so a user can see how their LLM-generated UI behaves on a slow
+// or flaky connection instead of only on localhost's effectively-infinite bandwidth.
+type NetworkProfile struct {
+	Name      string  `json:"name"`
+	ReadKBps  int     `json:"readKBps"`  // 0 disables read throttling
+	WriteKBps int     `json:"writeKBps"` // 0 disables write throttling
+	LatencyMs int     `json:"latencyMs"` // extra latency injected per request
+	LossPct   float64 `json:"lossPct"`   // probability (0-1) a request is dropped/500'd
+}
+
+// Named presets matching common real-world network conditions
+var (
+	NetworkProfileFast3G  = NetworkProfile{Name: "Fast 3G", ReadKBps: 180, WriteKBps: 84, LatencyMs: 150}
+	NetworkProfileSlow3G  = NetworkProfile{Name: "Slow 3G", ReadKBps: 50, WriteKBps: 50, LatencyMs: 400}
+	NetworkProfileOffline = NetworkProfile{Name: "Offline", LossPct: 1}
+	NetworkProfileNone    = NetworkProfile{Name: "None"}
+)
+
+// NetworkProfileByName resolves a preset by name, returning NetworkProfileNone if unknown
+func NetworkProfileByName(name string) NetworkProfile {
+	switch name {
+	case "Fast 3G":
+		return NetworkProfileFast3G
+	case "Slow 3G":
+		return NetworkProfileSlow3G
+	case "Offline":
+		return NetworkProfileOffline
+	default:
+		return NetworkProfileNone
+	}
+}
+
+// tokenBucket implements the classic token bucket rate limiter: a bucket with
+// capacity C bytes refills at R bytes/sec, and Take(n) blocks until n bytes
+// are available. A rate of 0 disables throttling (Take returns immediately).
+type tokenBucket struct {
+	mu       sync.Mutex
+	capacity float64
+	rate     float64 // bytes/sec, 0 = unlimited
+	tokens   float64
+	last     time.Time
+}
+
+func newTokenBucket(kbps int) *tokenBucket {
+	rate := float64(kbps) * 1024
+	return &tokenBucket{
+		capacity: rate, // 1 second worth of burst capacity
+		rate:     rate,
+		tokens:   rate,
+		last:     time.Now(),
+	}
+}
+
+// Take blocks until n bytes worth of tokens are available
+func (b *tokenBucket) Take(n int) {
+	if b.rate <= 0 {
+		return
+	}
+
+	for {
+		b.mu.Lock()
+		now := time.Now()
+		elapsed := now.Sub(b.last).Seconds()
+		b.tokens = min(b.capacity, b.tokens+elapsed*b.rate)
+		b.last = now
+
+		if b.tokens >= float64(n) {
+			b.tokens -= float64(n)
+			b.mu.Unlock()
+			return
+		}
+
+		// Not enough tokens yet: figure out how long until there will be
+		deficit := float64(n) - b.tokens
+		wait := time.Duration(deficit / b.rate * float64(time.Second))
+		b.mu.Unlock()
+
+		if wait > 50*time.Millisecond {
+			wait = 50 * time.Millisecond // recheck periodically instead of oversleeping
+		}
+		time.Sleep(wait)
+	}
+}
+
+func min(a, b float64) float64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// SlowListener wraps a net.Listener so every accepted connection is throttled
+// by independent read and write token buckets
+type SlowListener struct {
+	net.Listener
+	profile NetworkProfile
+}
+
+// NewSlowListener wraps inner with the given network profile. If profile has no
+// throttling configured, inner is returned unwrapped.
+func NewSlowListener(inner net.Listener, profile NetworkProfile) net.Listener {
+	if profile.ReadKBps <= 0 && profile.WriteKBps <= 0 {
+		return inner
+	}
+	return &SlowListener{Listener: inner, profile: profile}
+}
+
+// Accept wraps each accepted connection in a throttled conn
+func (l *SlowListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+
+	return &slowConn{
+		Conn:        conn,
+		readBucket:  newTokenBucket(l.profile.ReadKBps),
+		writeBucket: newTokenBucket(l.profile.WriteKBps),
+	}, nil
+}
+
+// slowConn throttles Read/Write through independent token buckets
+type slowConn struct {
+	net.Conn
+	readBucket  *tokenBucket
+	writeBucket *tokenBucket
+}
+
+// Read throttles against the bytes actually read, not len(p): callers
+// (net/http in particular) pass large fixed-size buffers that are rarely
+// filled, and charging for the whole buffer upfront would throttle far
+// below the configured rate.
+func (c *slowConn) Read(p []byte) (int, error) {
+	n, err := c.Conn.Read(p)
+	if n > 0 {
+		c.readBucket.Take(n)
+	}
+	return n, err
+}
+
+func (c *slowConn) Write(p []byte) (int, error) {
+	c.writeBucket.Take(len(p))
+	return c.Conn.Write(p)
+}
+
+// ShouldDrop returns true if this request should be dropped/500'd per the
+// profile's loss probability, and the injected latency to apply beforehand
+func ShouldDrop(profile NetworkProfile) (bool, time.Duration) {
+	latency := time.Duration(profile.LatencyMs) * time.Millisecond
+	if profile.LossPct <= 0 {
+		return false, latency
+	}
+	return rand.Float64() < profile.LossPct, latency
+}
@@ -0,0 +1,62 @@
+package proxy
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+// fakeConn is a minimal net.Conn that always returns exactly n bytes from a
+// fixed read, regardless of the caller's buffer size, so Read's accounting
+// can be tested without a real socket.
+type fakeConn struct {
+	net.Conn
+	n int
+}
+
+func (c *fakeConn) Read(p []byte) (int, error) {
+	return c.n, nil
+}
+
+func TestSlowConnReadChargesActualBytes(t *testing.T) {
+	bucket := newTokenBucket(1) // 1 KBps, so the 1024-byte capacity is easy to reason about
+	conn := &slowConn{Conn: &fakeConn{n: 10}, readBucket: bucket}
+
+	buf := make([]byte, 4096) // much larger than the 10 bytes actually read
+	if _, err := conn.Read(buf); err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+
+	bucket.mu.Lock()
+	tokens := bucket.tokens
+	bucket.mu.Unlock()
+
+	want := bucket.capacity - 10
+	if tokens < want-1 || tokens > want+1 {
+		t.Fatalf("expected ~%.0f tokens remaining after a 10-byte read into a 4096-byte buffer, got %.2f", want, tokens)
+	}
+}
+
+func TestTokenBucketTakeBlocksUntilRefilled(t *testing.T) {
+	bucket := newTokenBucket(1) // 1 KBps capacity/refill rate
+	bucket.tokens = 0
+	bucket.last = time.Now()
+
+	start := time.Now()
+	bucket.Take(512) // half the 1024-byte/sec rate should take ~500ms
+	elapsed := time.Since(start)
+
+	if elapsed < 400*time.Millisecond {
+		t.Fatalf("Take(512) at 1 KBps returned too early: %v", elapsed)
+	}
+}
+
+func TestTokenBucketUnlimitedDoesNotBlock(t *testing.T) {
+	bucket := newTokenBucket(0)
+
+	start := time.Now()
+	bucket.Take(1 << 20)
+	if elapsed := time.Since(start); elapsed > 10*time.Millisecond {
+		t.Fatalf("Take on an unlimited bucket should return immediately, took %v", elapsed)
+	}
+}
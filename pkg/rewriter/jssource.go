@@ -0,0 +1,138 @@
+package rewriter
+
+import "strings"
+
+// rewriteJSStringLiterals scans src as a token stream — distinguishing line
+// comments, block comments, '/"/` string literals, and JSX tag boundaries
+// from everything else — and rewrites a string literal only when its
+// content is exactly oldPath, it immediately follows one of targetAttrs
+// (e.g. `src="..."`, `src = "..."`, or JSX's `src={"..."}`), AND that
+// attribute name was seen inside a JSX tag's opening `<tag ...>`. Tracking
+// tag position is what keeps a bare `const src = "hero.jpg"` variable
+// declaration — which looks identical to a JSX attribute at the token level
+// — from being treated as one, so a comment mentioning the old path, an
+// unrelated string with the same filename, or an unrelated variable sharing
+// an attribute's name is never touched.
+//
+// This is a hand-rolled scanner, not a real parser, so unlike a full AST it
+// can't fail on a syntax error — it always terminates, copying anything it
+// doesn't recognize straight through. RewriteFile's caller should still
+// treat a future parsing failure (e.g. if this is swapped for a real one) as
+// a signal to fall back to a raw string replace instead of silently
+// skipping the file.
+func rewriteJSStringLiterals(content []byte, oldPath, newPath string) ([]byte, Result, error) {
+	src := string(content)
+	n := len(src)
+	var out strings.Builder
+	edits := 0
+	lastIdent := ""
+	inTag := false
+
+	for i := 0; i < n; {
+		c := src[i]
+
+		switch {
+		case c == '/' && i+1 < n && src[i+1] == '/':
+			end := strings.IndexByte(src[i:], '\n')
+			if end == -1 {
+				out.WriteString(src[i:])
+				i = n
+				continue
+			}
+			out.WriteString(src[i : i+end+1])
+			i += end + 1
+			lastIdent = ""
+
+		case c == '/' && i+1 < n && src[i+1] == '*':
+			end := strings.Index(src[i+2:], "*/")
+			if end == -1 {
+				out.WriteString(src[i:])
+				i = n
+				continue
+			}
+			stop := i + 2 + end + 2
+			out.WriteString(src[i:stop])
+			i = stop
+			lastIdent = ""
+
+		case c == '"' || c == '\'' || c == '`':
+			quote := c
+			j := i + 1
+			for j < n && src[j] != quote {
+				if src[j] == '\\' && j+1 < n {
+					j += 2
+					continue
+				}
+				j++
+			}
+			if j >= n {
+				// unterminated literal; copy the rest through unchanged
+				out.WriteString(src[i:])
+				i = n
+				continue
+			}
+
+			literal := src[i+1 : j]
+			if literal == oldPath && inTag && targetAttrs[strings.ToLower(lastIdent)] {
+				out.WriteByte(quote)
+				out.WriteString(newPath)
+				out.WriteByte(quote)
+				edits++
+			} else {
+				out.WriteString(src[i : j+1])
+			}
+			lastIdent = ""
+			i = j + 1
+
+		// A '<' directly followed by an identifier or '/' opens a JSX tag
+		// (start or closing); its matching unquoted '>' closes it. Only
+		// inside that span can an identifier be a real JSX attribute name
+		// rather than, say, a bare variable on the left of an assignment.
+		case c == '<' && i+1 < n && (isIdentStart(src[i+1]) || src[i+1] == '/'):
+			inTag = true
+			out.WriteByte(c)
+			lastIdent = ""
+			i++
+
+		case c == '>':
+			inTag = false
+			out.WriteByte(c)
+			lastIdent = ""
+			i++
+
+		case isIdentStart(c):
+			j := i + 1
+			for j < n && isIdentPart(src[j]) {
+				j++
+			}
+			lastIdent = src[i:j]
+			out.WriteString(src[i:j])
+			i = j
+
+		// Whitespace, `=`, and `{` are left between an attribute name and its
+		// string value by both `src="x"` and JSX's `src={"x"}`, so preserve
+		// lastIdent across them instead of clearing it like the default case
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '=' || c == '{':
+			out.WriteByte(c)
+			i++
+
+		default:
+			out.WriteByte(c)
+			lastIdent = ""
+			i++
+		}
+	}
+
+	if edits == 0 {
+		return content, Result{}, nil
+	}
+	return []byte(out.String()), Result{Edits: edits}, nil
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || c == '$' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIdentPart(c byte) bool {
+	return isIdentStart(c) || (c >= '0' && c <= '9')
+}
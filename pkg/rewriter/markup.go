@@ -0,0 +1,132 @@
+package rewriter
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// rewriteTemplateMarkup rewrites target-attribute values inside content's
+// first <template>...</template> block (Vue and Svelte both serve markup
+// from one), leaving everything outside it — including <script> blocks,
+// which the caller handles separately as JS — untouched
+func rewriteTemplateMarkup(content []byte, oldPath, newPath string) ([]byte, Result, error) {
+	start, end, ok := findTemplateBlock(content)
+	if !ok {
+		return content, Result{}, nil
+	}
+
+	rewrittenBody, edits, err := rewriteHTMLAttrs(content[start:end], oldPath, newPath)
+	if err != nil {
+		return nil, Result{}, err
+	}
+	if edits == 0 {
+		return content, Result{}, nil
+	}
+
+	var out bytes.Buffer
+	out.Write(content[:start])
+	out.Write(rewrittenBody)
+	out.Write(content[end:])
+	return out.Bytes(), Result{Edits: edits}, nil
+}
+
+// findTemplateBlock returns the byte range of content between the opening
+// and closing <template> tags (exclusive of the tags themselves)
+func findTemplateBlock(content []byte) (start, end int, ok bool) {
+	lower := bytes.ToLower(content)
+
+	openIdx := bytes.Index(lower, []byte("<template"))
+	if openIdx == -1 {
+		return 0, 0, false
+	}
+
+	openTagEnd := bytes.IndexByte(content[openIdx:], '>')
+	if openTagEnd == -1 {
+		return 0, 0, false
+	}
+	bodyStart := openIdx + openTagEnd + 1
+
+	closeIdx := bytes.Index(lower[bodyStart:], []byte("</template>"))
+	if closeIdx == -1 {
+		return 0, 0, false
+	}
+
+	return bodyStart, bodyStart + closeIdx, true
+}
+
+// rewriteHTMLAttrs streams body through an HTML tokenizer (the same
+// approach proxy.InjectScript uses for its own markup rewrite) and replaces
+// any target attribute whose value is exactly oldPath, reusing z.Raw() for
+// every token it doesn't need to touch so untouched markup round-trips
+// byte-for-byte
+func rewriteHTMLAttrs(body []byte, oldPath, newPath string) ([]byte, int, error) {
+	z := html.NewTokenizer(bytes.NewReader(body))
+	var out bytes.Buffer
+	edits := 0
+
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			if err := z.Err(); err != io.EOF {
+				return nil, 0, err
+			}
+			break
+		}
+
+		if tt != html.StartTagToken && tt != html.SelfClosingTagToken {
+			out.Write(z.Raw())
+			continue
+		}
+
+		name, hasAttr := z.TagName()
+		if !hasAttr {
+			out.Write(z.Raw())
+			continue
+		}
+
+		var attrs []html.Attribute
+		tagEdits := 0
+		for {
+			key, val, more := z.TagAttr()
+			attr := html.Attribute{Key: string(key), Val: string(val)}
+			if targetAttrs[strings.ToLower(attr.Key)] && attr.Val == oldPath {
+				attr.Val = newPath
+				tagEdits++
+			}
+			attrs = append(attrs, attr)
+			if !more {
+				break
+			}
+		}
+
+		if tagEdits == 0 {
+			out.Write(z.Raw())
+			continue
+		}
+
+		edits += tagEdits
+		out.WriteString(renderStartTag(string(name), attrs, tt == html.SelfClosingTagToken))
+	}
+
+	return out.Bytes(), edits, nil
+}
+
+// renderStartTag re-serializes a start tag after one of its attributes was
+// rewritten; html.EscapeString keeps the new value safe to embed
+func renderStartTag(name string, attrs []html.Attribute, selfClosing bool) string {
+	var b strings.Builder
+	b.WriteByte('<')
+	b.WriteString(name)
+	for _, a := range attrs {
+		fmt.Fprintf(&b, ` %s="%s"`, a.Key, html.EscapeString(a.Val))
+	}
+	if selfClosing {
+		b.WriteString(" /")
+	}
+	b.WriteByte('>')
+	return b.String()
+}
@@ -0,0 +1,68 @@
+// Package rewriter edits framework source files in place to point at a new
+// asset path. Unlike a raw strings.ReplaceAll across the whole file, it only
+// touches string literals in known asset-bearing positions — the
+// src/href/poster/background attribute of a markup tag, or the matching
+// JSX-attribute position inside a JSX tag in JS/TSX — so a comment, an unrelated
+// string that legitimately contains the old path, or a short filename like
+// "logo.png" appearing elsewhere in the file can't be corrupted.
+package rewriter
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// targetAttrs are the only attribute/property names RewriteFile is allowed
+// to rewrite a matching string value under
+var targetAttrs = map[string]bool{
+	"src":        true,
+	"href":       true,
+	"poster":     true,
+	"background": true,
+}
+
+// Result reports how many asset references RewriteFile changed in a file
+type Result struct {
+	Edits int
+}
+
+// RewriteFile rewrites every exact occurrence of oldPath as an asset
+// reference in the file at path to newPath, returning how many it changed.
+// The file's extension selects the parser: .vue/.svelte are scanned as HTML
+// scoped to their <template> block, everything else as a JS/TSX token
+// stream. The file on disk is left untouched if no matching reference is
+// found or an error is returned.
+func RewriteFile(path, oldPath, newPath string) (Result, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return Result{}, err
+	}
+
+	var rewritten []byte
+	var result Result
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".vue", ".svelte":
+		rewritten, result, err = rewriteTemplateMarkup(content, oldPath, newPath)
+	default:
+		rewritten, result, err = rewriteJSStringLiterals(content, oldPath, newPath)
+	}
+	if err != nil {
+		return Result{}, err
+	}
+	if result.Edits == 0 {
+		return result, nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return Result{}, err
+	}
+	if err := os.WriteFile(path, rewritten, info.Mode()); err != nil {
+		return Result{}, fmt.Errorf("failed to write file %s: %w", path, err)
+	}
+
+	return result, nil
+}
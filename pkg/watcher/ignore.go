@@ -0,0 +1,162 @@
+package watcher
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ignorePattern is one compiled line from a .gitignore file (or an extra
+// pattern from WatchOptions), scoped to the directory it was declared in
+type ignorePattern struct {
+	re      *regexp.Regexp
+	negate  bool
+	dirOnly bool
+	baseDir string // directory the pattern is relative to
+}
+
+// ignoreMatcher accumulates gitignore patterns as the watcher walks the
+// project tree. Patterns are kept in discovery order (root to leaf, and
+// top to bottom within a file) since that's the order git itself applies
+// them in: later patterns win, but a path excluded by a parent directory's
+// pattern can't be re-included by a deeper `!` negation.
+type ignoreMatcher struct {
+	patterns []ignorePattern
+}
+
+// newIgnoreMatcher seeds a matcher with extra always-on patterns (from
+// WatchOptions.ExtraIgnores) rooted at projectDir
+func newIgnoreMatcher(projectDir string, extra []string) *ignoreMatcher {
+	m := &ignoreMatcher{}
+	for _, line := range extra {
+		if p, ok := compileIgnorePattern(line, projectDir); ok {
+			m.patterns = append(m.patterns, p)
+		}
+	}
+	return m
+}
+
+// loadDir reads dir/.gitignore, if present, and appends its patterns
+func (m *ignoreMatcher) loadDir(dir string) {
+	data, err := os.ReadFile(filepath.Join(dir, ".gitignore"))
+	if err != nil {
+		return
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(string(data)))
+	for scanner.Scan() {
+		if p, ok := compileIgnorePattern(scanner.Text(), dir); ok {
+			m.patterns = append(m.patterns, p)
+		}
+	}
+}
+
+// isIgnored reports whether path (absolute) should be excluded from watching
+func (m *ignoreMatcher) isIgnored(path string, isDir bool) bool {
+	ignored := false
+	for _, p := range m.patterns {
+		if p.dirOnly && !isDir {
+			continue
+		}
+
+		rel, err := filepath.Rel(p.baseDir, path)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			continue // pattern doesn't apply under this path
+		}
+		rel = filepath.ToSlash(rel)
+
+		if p.re.MatchString(rel) {
+			ignored = !p.negate
+		}
+	}
+	return ignored
+}
+
+// compileIgnorePattern parses a single .gitignore line into a matchable
+// pattern, or reports ok=false for blank lines and comments
+func compileIgnorePattern(line, baseDir string) (ignorePattern, bool) {
+	line = strings.TrimRight(line, " \t")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return ignorePattern{}, false
+	}
+
+	negate := false
+	if strings.HasPrefix(line, "!") {
+		negate = true
+		line = line[1:]
+	}
+	// A leading backslash escapes a literal `!` or `#`
+	if strings.HasPrefix(line, "\\") {
+		line = line[1:]
+	}
+
+	dirOnly := strings.HasSuffix(line, "/")
+	line = strings.TrimSuffix(line, "/")
+	if line == "" {
+		return ignorePattern{}, false
+	}
+
+	// A pattern containing a slash anywhere but the end is anchored to baseDir;
+	// one with no slash at all matches at any depth under baseDir
+	anchored := strings.Contains(line, "/")
+	line = strings.TrimPrefix(line, "/")
+
+	return ignorePattern{
+		re:      gitignoreGlobToRegexp(line, anchored),
+		negate:  negate,
+		dirOnly: dirOnly,
+		baseDir: baseDir,
+	}, true
+}
+
+// gitignoreGlobToRegexp translates a single gitignore glob into a regexp that
+// matches the whole relative path (as produced by isIgnored). Supports `*`,
+// `?`, `[...]`, and `**` (any number of path segments, including none).
+func gitignoreGlobToRegexp(glob string, anchored bool) *regexp.Regexp {
+	var out strings.Builder
+	out.WriteString("^")
+
+	if !anchored {
+		out.WriteString("(?:.*/)?")
+	}
+
+	runes := []rune(glob)
+	for i := 0; i < len(runes); i++ {
+		switch c := runes[i]; c {
+		case '*':
+			if i+1 < len(runes) && runes[i+1] == '*' {
+				// consume the full `**` (with optional trailing slash) as "zero or more segments"
+				j := i + 2
+				if j < len(runes) && runes[j] == '/' {
+					j++
+				}
+				out.WriteString(".*")
+				i = j - 1
+			} else {
+				out.WriteString("[^/]*")
+			}
+		case '?':
+			out.WriteString("[^/]")
+		case '.', '+', '(', ')', '^', '$', '|', '{', '}':
+			out.WriteString(regexp.QuoteMeta(string(c)))
+		case '[':
+			j := i + 1
+			for j < len(runes) && runes[j] != ']' {
+				j++
+			}
+			if j < len(runes) {
+				out.WriteString("[" + string(runes[i+1:j]) + "]")
+				i = j
+			} else {
+				out.WriteString(regexp.QuoteMeta(string(c)))
+			}
+		default:
+			out.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	out.WriteString("(?:/.*)?$")
+	return regexp.MustCompile(out.String())
+}
@@ -1,9 +1,11 @@
 package watcher
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"sync"
 	"time"
 
@@ -12,95 +14,141 @@ import (
 	"github.com/thetronjohnson/layrr/pkg/status"
 )
 
-// Watcher watches files for changes and notifies connected WebSocket clients
+// WatchOptions configures the watcher beyond its default .gitignore-driven
+// behavior. The zero value is a sensible default: no extra ignores, the
+// built-in extension list, and a 300ms debounce.
+type WatchOptions struct {
+	ExtraIgnores    []string // additional gitignore-style patterns, rooted at the project directory
+	ExtraExtensions []string // additional file extensions that should trigger a reload
+	DebounceMs      int      // reload debounce window; 0 means the 300ms default
+
+	// MatchFile, when set, overrides the extension-based relevance check:
+	// it's called with the project-relative, slash-separated path of every
+	// non-ignored change, and only matches are queued for reload. Used by
+	// devserver.Watcher to restart on config changes instead of every asset edit.
+	MatchFile func(relPath string) bool
+}
+
+// defaultIgnores apply on every project regardless of .gitignore contents,
+// since these are never meaningful to a dev-server reload and walking them
+// (node_modules especially) is expensive
+var defaultIgnores = []string{".git/", ".DS_Store", "node_modules/", ".next/", "dist/"}
+
+var defaultExtensions = []string{".vue", ".jsx", ".tsx", ".js", ".ts", ".css", ".scss", ".sass", ".less", ".html"}
+
+// reloadMessage is the WebSocket payload sent to watcher clients on a change
+type reloadMessage struct {
+	Type  string   `json:"type"`
+	Files []string `json:"files"`
+}
+
+// Watcher watches files for changes and notifies connected WebSocket clients.
+// Directories are discovered recursively and honor .gitignore (parsed at every
+// level, root to leaf) plus any WatchOptions.ExtraIgnores; new subdirectories
+// created after startup are picked up automatically.
 type Watcher struct {
-	fsWatcher *fsnotify.Watcher
+	fsWatcher  *fsnotify.Watcher
+	projectDir string
+	ignore     *ignoreMatcher
+	extensions []string
+	matchFile  func(relPath string) bool
+
 	clients   map[*websocket.Conn]bool
 	clientsMu sync.RWMutex
+
 	debounce  time.Duration
 	timer     *time.Timer
+	pending   map[string]bool
+	pendingMu sync.Mutex
+
 	verbose   bool
 	display   *status.Display
-	callbacks []func() // Callback functions to call on file changes
+	callbacks []func([]string) // invoked with the batch of changed (project-relative) files
 }
 
-// NewWatcher creates a new file watcher
-func NewWatcher(projectDir string, verbose bool, display *status.Display) (*Watcher, error) {
+// NewWatcher creates a new file watcher rooted at projectDir
+func NewWatcher(projectDir string, opts WatchOptions, verbose bool, display *status.Display) (*Watcher, error) {
 	fsWatcher, err := fsnotify.NewWatcher()
 	if err != nil {
 		return nil, fmt.Errorf("failed to create file watcher: %w", err)
 	}
 
+	absDir, err := filepath.Abs(projectDir)
+	if err != nil {
+		return nil, err
+	}
+
+	debounce := 300 * time.Millisecond
+	if opts.DebounceMs > 0 {
+		debounce = time.Duration(opts.DebounceMs) * time.Millisecond
+	}
+
+	extraIgnores := append(append([]string{}, defaultIgnores...), opts.ExtraIgnores...)
+	extensions := append(append([]string{}, defaultExtensions...), opts.ExtraExtensions...)
+
 	w := &Watcher{
-		fsWatcher: fsWatcher,
-		clients:   make(map[*websocket.Conn]bool),
-		debounce:  300 * time.Millisecond, // 300ms debounce
-		verbose:   verbose,
-		display:   display,
+		fsWatcher:  fsWatcher,
+		projectDir: absDir,
+		ignore:     newIgnoreMatcher(absDir, extraIgnores),
+		extensions: extensions,
+		matchFile:  opts.MatchFile,
+		clients:    make(map[*websocket.Conn]bool),
+		debounce:   debounce,
+		pending:    make(map[string]bool),
+		verbose:    verbose,
+		display:    display,
 	}
 
-	// Add the project directory recursively
-	if err := w.addDirRecursive(projectDir); err != nil {
+	if err := w.addDirRecursive(absDir); err != nil {
 		return nil, err
 	}
 
-	// Start watching
 	go w.watch()
 
 	return w, nil
 }
 
-// addDirRecursive adds a directory and all its subdirectories to the watcher
+// addDirRecursive loads dir's .gitignore, watches dir itself, then recurses
+// into its non-ignored subdirectories. Called both at startup (for the whole
+// tree) and whenever fsnotify reports a newly created directory.
 func (w *Watcher) addDirRecursive(dir string) error {
-	// Get absolute path of the directory
-	absDir, err := filepath.Abs(dir)
+	entries, err := os.ReadDir(dir)
 	if err != nil {
-		return err
+		if w.verbose {
+			fmt.Printf("[Watcher] Failed to read directory %s: %v\n", dir, err)
+		}
+		return nil
 	}
 
-	return filepath.Walk(absDir, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
+	w.ignore.loadDir(dir)
 
-		// Get absolute path
-		absPath, err := filepath.Abs(path)
-		if err != nil {
-			return err
+	if err := w.fsWatcher.Add(dir); err != nil {
+		if w.verbose {
+			fmt.Printf("[Watcher] Failed to watch %s: %v\n", dir, err)
 		}
+	} else if w.verbose {
+		fmt.Printf("[Watcher] Added directory to watcher: %s\n", dir)
+	}
 
-		// Only watch paths that are within the project directory
-		// Skip the Layrr app's own directories
-		if !filepath.HasPrefix(absPath, absDir) {
-			return filepath.SkipDir
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
 		}
 
-		// Skip node_modules, .git, dist, build, wailsjs directories
-		if info != nil && info.IsDir() {
-			name := filepath.Base(path)
-			if name == "node_modules" || name == ".git" || name == "dist" || name == "build" || name == ".next" || name == "wailsjs" {
-				if w.verbose {
-					fmt.Printf("[Watcher] Skipping directory: %s\n", path)
-				}
-				return filepath.SkipDir
+		path := filepath.Join(dir, entry.Name())
+		if w.ignore.isIgnored(path, true) {
+			if w.verbose {
+				fmt.Printf("[Watcher] Skipping ignored directory: %s\n", path)
 			}
+			continue
 		}
 
-		// Only add directories (not individual files)
-		if info != nil && info.IsDir() {
-			if err := w.fsWatcher.Add(path); err != nil {
-				if w.verbose {
-					fmt.Printf("[Watcher] Failed to watch %s: %v\n", path, err)
-				}
-			} else {
-				if w.verbose {
-					fmt.Printf("[Watcher] Added directory to watcher: %s\n", path)
-				}
-			}
+		if err := w.addDirRecursive(path); err != nil {
+			return err
 		}
+	}
 
-		return nil
-	})
+	return nil
 }
 
 // watch monitors file system events
@@ -111,13 +159,7 @@ func (w *Watcher) watch() {
 			if !ok {
 				return
 			}
-
-			// Only watch for write and create events on relevant files
-			if event.Op&(fsnotify.Write|fsnotify.Create) != 0 {
-				if w.isRelevantFile(event.Name) {
-					w.debounceReload()
-				}
-			}
+			w.handleEvent(event)
 
 		case err, ok := <-w.fsWatcher.Errors:
 			if !ok {
@@ -130,12 +172,64 @@ func (w *Watcher) watch() {
 	}
 }
 
-// isRelevantFile checks if a file should trigger a reload
+// handleEvent routes a single fsnotify event: new directories are watched
+// (and recursed into), removed/renamed paths are unwatched, and relevant
+// file changes are queued for the debounced reload
+func (w *Watcher) handleEvent(event fsnotify.Event) {
+	switch {
+	case event.Op&fsnotify.Create != 0:
+		if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+			if !w.ignore.isIgnored(event.Name, true) {
+				if err := w.addDirRecursive(event.Name); err != nil && w.verbose {
+					fmt.Printf("[Watcher] Failed to watch new directory %s: %v\n", event.Name, err)
+				}
+			}
+			return // the directory itself appearing isn't a reload-worthy change
+		}
+		w.queueReload(event.Name)
+
+	case event.Op&(fsnotify.Remove|fsnotify.Rename) != 0:
+		// Remove is harmless (and returns an error) for paths we never added
+		// as watch targets, e.g. plain files; ignore the result either way
+		_ = w.fsWatcher.Remove(event.Name)
+		w.queueReload(event.Name)
+
+	case event.Op&fsnotify.Write != 0:
+		w.queueReload(event.Name)
+	}
+}
+
+// queueReload adds path to the pending batch if it's relevant and (re)starts
+// the debounce timer
+func (w *Watcher) queueReload(path string) {
+	if !w.isRelevantFile(path) {
+		return
+	}
+
+	w.pendingMu.Lock()
+	w.pending[path] = true
+	w.pendingMu.Unlock()
+
+	w.debounceReload()
+}
+
+// isRelevantFile checks if a file should trigger a reload: not ignored, and
+// either matched by MatchFile (when set) or one of the watched extensions
 func (w *Watcher) isRelevantFile(path string) bool {
-	ext := filepath.Ext(path)
-	relevantExts := []string{".vue", ".jsx", ".tsx", ".js", ".ts", ".css", ".scss", ".sass", ".less", ".html"}
+	if w.ignore.isIgnored(path, false) {
+		return false
+	}
 
-	for _, relevantExt := range relevantExts {
+	if w.matchFile != nil {
+		rel, err := filepath.Rel(w.projectDir, path)
+		if err != nil {
+			rel = path
+		}
+		return w.matchFile(filepath.ToSlash(rel))
+	}
+
+	ext := filepath.Ext(path)
+	for _, relevantExt := range w.extensions {
 		if ext == relevantExt {
 			return true
 		}
@@ -144,45 +238,67 @@ func (w *Watcher) isRelevantFile(path string) bool {
 	return false
 }
 
-// debounceReload debounces reload notifications
+// debounceReload (re)schedules flushPending after the debounce window,
+// coalescing a burst of changes (e.g. a save-all) into one notification
 func (w *Watcher) debounceReload() {
 	if w.timer != nil {
 		w.timer.Stop()
 	}
 
-	w.timer = time.AfterFunc(w.debounce, func() {
-		w.notifyClients()
-	})
+	w.timer = time.AfterFunc(w.debounce, w.flushPending)
+}
+
+// flushPending drains the pending file set and notifies clients with the batch
+func (w *Watcher) flushPending() {
+	w.pendingMu.Lock()
+	files := make([]string, 0, len(w.pending))
+	for path := range w.pending {
+		if rel, err := filepath.Rel(w.projectDir, path); err == nil {
+			files = append(files, filepath.ToSlash(rel))
+		} else {
+			files = append(files, path)
+		}
+	}
+	w.pending = make(map[string]bool)
+	w.pendingMu.Unlock()
+
+	sort.Strings(files)
+	w.notifyClients(files)
 }
 
-// notifyClients sends a reload message to all connected WebSocket clients
-func (w *Watcher) notifyClients() {
+// notifyClients sends a reload message naming the changed files to all
+// connected WebSocket clients and invokes any registered callbacks
+func (w *Watcher) notifyClients(files []string) {
 	w.clientsMu.RLock()
 	defer w.clientsMu.RUnlock()
 
-	// Display is handled by TUI now - no direct printing needed
-
 	if w.verbose {
-		fmt.Printf("[Watcher] Notifying %d clients to reload\n", len(w.clients))
+		fmt.Printf("[Watcher] Notifying %d clients to reload: %v\n", len(w.clients), files)
+	}
+
+	payload, err := json.Marshal(reloadMessage{Type: "reload", Files: files})
+	if err != nil {
+		if w.verbose {
+			fmt.Printf("[Watcher] Failed to encode reload message: %v\n", err)
+		}
+		return
 	}
 
 	for client := range w.clients {
-		err := client.WriteMessage(websocket.TextMessage, []byte(`{"type":"reload"}`))
-		if err != nil {
+		if err := client.WriteMessage(websocket.TextMessage, payload); err != nil {
 			if w.verbose {
 				fmt.Printf("[Watcher] Failed to notify client: %v\n", err)
 			}
 		}
 	}
 
-	// Call registered callbacks
 	for _, callback := range w.callbacks {
-		callback()
+		callback(files)
 	}
 }
 
-// OnChange registers a callback to be called when files change
-func (w *Watcher) OnChange(callback func()) {
+// OnChange registers a callback invoked with the batch of changed (project-relative) files
+func (w *Watcher) OnChange(callback func(files []string)) {
 	w.callbacks = append(w.callbacks, callback)
 }
 